@@ -0,0 +1,109 @@
+package main
+
+import (
+	"os"
+
+	"github.com/moeryomenko/gupdeps/internal/config"
+	"github.com/moeryomenko/gupdeps/internal/dependencies"
+	"github.com/moeryomenko/gupdeps/internal/models"
+	"github.com/moeryomenko/gupdeps/internal/report"
+	"github.com/moeryomenko/gupdeps/internal/utils"
+)
+
+// Exit codes of the check subcommand. When several failing conditions are
+// present the most severe one, with the highest code, wins.
+var checkExitCodes = map[string]int{
+	config.ConditionUpdates:    10,
+	config.ConditionRisky:      11,
+	config.ConditionRetracted:  12,
+	config.ConditionVulnerable: 13,
+	config.ConditionErrors:     14,
+}
+
+// conditionLabels describes the check conditions in the summary
+var conditionLabels = map[string]string{
+	config.ConditionUpdates:    "Safe updates available",
+	config.ConditionRisky:      "Risky updates available",
+	config.ConditionRetracted:  "Retracted versions required",
+	config.ConditionVulnerable: "Vulnerable dependencies present",
+	config.ConditionErrors:     "Dependencies failed to analyze",
+}
+
+// runCheck analyzes the dependencies without modifying any file and returns the
+// exit code of the most severe failing condition, 0 when none fails
+func runCheck(updater *dependencies.DependencyUpdater, logger *utils.Logger, cfg *config.Config, opts *options) (int, error) {
+	deps, err := fetchAndDisplayDependencies(updater, logger)
+	if err != nil {
+		return 0, err
+	}
+
+	results, err := analyzeDependencies(updater, logger, deps)
+	if err != nil {
+		return 0, err
+	}
+
+	displayRejectedUpdates(logger, results.rejected)
+	displayPendingUpdates(logger, deps)
+	displayIgnoredUpdates(logger, deps)
+
+	rep := report.New(opts.project, deps, results.analyses, results.errs)
+	if opts.format != "text" {
+		if err := report.Write(os.Stdout, opts.format, rep); err != nil {
+			return 0, err
+		}
+	}
+
+	return checkExitCode(logger, cfg, checkConditions(rep, updater.RejectedAsRisky)), nil
+}
+
+// checkExitCode prints the conditions present and returns the exit code of the
+// most severe one that fails the check, 0 when none does
+func checkExitCode(logger *utils.Logger, cfg *config.Config, present []string) int {
+	if len(present) == 0 {
+		logger.Success("All dependencies are up to date")
+		return 0
+	}
+
+	code := 0
+	logger.Print("\n🩺 Check Summary:")
+	for _, condition := range present {
+		if !cfg.Check.FailsOn(condition) {
+			logger.Print("  ➖ %s (%s, ignored)", conditionLabels[condition], condition)
+			continue
+		}
+		logger.Print("  ❗ %s (%s, exit code %d)", conditionLabels[condition], condition, checkExitCodes[condition])
+		code = max(code, checkExitCodes[condition])
+	}
+
+	return code
+}
+
+// checkConditions returns the conditions present in a report, in order of
+// severity. Only rejections that risky reports as such make updates risky.
+func checkConditions(rep *report.Report, risky func(*models.UpdateAnalysis) bool) []string {
+	found := map[string]bool{
+		config.ConditionUpdates: rep.Summary.Approved > 0,
+		config.ConditionErrors:  rep.Summary.Errors > 0,
+	}
+
+	for i := range rep.Dependencies {
+		entry := &rep.Dependencies[i]
+		if entry.Decision == report.DecisionRejected && risky(entry.Analysis) {
+			found[config.ConditionRisky] = true
+		}
+		if len(entry.Retracted) > 0 {
+			found[config.ConditionRetracted] = true
+		}
+		if entry.Analysis != nil && len(entry.Analysis.Vulnerabilities) > 0 {
+			found[config.ConditionVulnerable] = true
+		}
+	}
+
+	var present []string
+	for _, condition := range config.CheckConditions {
+		if found[condition] {
+			present = append(present, condition)
+		}
+	}
+	return present
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/moeryomenko/gupdeps/internal/config"
+	"github.com/moeryomenko/gupdeps/internal/dependencies"
+	"github.com/moeryomenko/gupdeps/internal/models"
+	"github.com/moeryomenko/gupdeps/internal/report"
+	"github.com/moeryomenko/gupdeps/internal/utils"
+)
+
+func TestCheckRejections(t *testing.T) {
+	tests := []struct {
+		name     string
+		analysis models.UpdateAnalysis
+		want     int
+	}{
+		{
+			name: "no improvements",
+			analysis: models.UpdateAnalysis{
+				RejectionReason: "No significant improvements found",
+				Risk:            &models.RiskAssessment{Score: 5, Level: models.RiskLow},
+			},
+			want: 0,
+		},
+		{
+			name: "license veto",
+			analysis: models.UpdateAnalysis{
+				RejectionReason: "License changed from MIT to AGPL-3.0, which is not in the allowed licenses",
+				Risk:            &models.RiskAssessment{Score: 10, Level: models.RiskLow},
+			},
+			want: 0,
+		},
+		{
+			name: "risk level",
+			analysis: models.UpdateAnalysis{
+				RejectionReason: "Risk score 75 (critical) exceeds the maximum approved level medium",
+				Risk:            &models.RiskAssessment{Score: 75, Level: models.RiskCritical},
+			},
+			want: checkExitCodes[config.ConditionRisky],
+		},
+		{
+			name: "breaking changes",
+			analysis: models.UpdateAnalysis{
+				Commits:         []models.CommitInfo{{Message: "remove the v1 API", Category: "break"}},
+				RejectionReason: "Contains 1 breaking changes",
+				Risk:            &models.RiskAssessment{Score: 10, Level: models.RiskLow},
+			},
+			want: checkExitCodes[config.ConditionRisky],
+		},
+	}
+
+	logger := utils.NewLogger(false)
+	logger.SetOutput(io.Discard)
+	cfg := config.Default()
+	updater := dependencies.NewDependencyUpdater(t.TempDir(), cfg, logger)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dep := &models.Dependency{
+				Name:           "example.com/a",
+				CurrentVersion: "v1.0.0",
+				LatestVersion:  "v1.1.0",
+				UpdateNeeded:   true,
+			}
+			analysis := tt.analysis
+			analysis.Dependency = dep
+
+			rep := report.New("/project", []*models.Dependency{dep}, []*models.UpdateAnalysis{&analysis}, []error{nil})
+			if code := checkExitCode(logger, cfg, checkConditions(rep, updater.RejectedAsRisky)); code != tt.want {
+				t.Errorf("exit code = %d, want %d", code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,420 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/moeryomenko/gupdeps/internal/config"
+	"github.com/moeryomenko/gupdeps/internal/dependencies"
+	"github.com/moeryomenko/gupdeps/internal/utils"
+)
+
+// defaultCommand runs when no command is given
+const defaultCommand = "update"
+
+// command is a gupdeps command with its own flags
+type command struct {
+	name     string
+	args     string // arguments after the flags, as shown in the usage
+	nargs    int    // number of arguments required
+	summary  string
+	examples []string
+	flags    func(fs *flag.FlagSet, cf *configFlags, opts *options)
+	run      func(app *app, args []string) error
+}
+
+// app holds what a command runs with once its flags and the configuration are loaded
+type app struct {
+	logger     *utils.Logger
+	cfg        *config.Config
+	configFile string
+	updater    *dependencies.DependencyUpdater
+	opts       *options
+}
+
+// options holds the command-line options that are not part of the configuration
+type options struct {
+	path           string
+	verbose        bool
+	interactive    bool
+	batch          bool
+	out            string
+	verifyCommands []string
+	format         string
+	project        string // absolute path of the project, as shown in reports
+	script         string // keys replayed by the terminal UI instead of reading the terminal
+}
+
+// commands lists the gupdeps commands in the order they are shown in the help
+var commands = []*command{
+	{
+		name:     "list",
+		summary:  "List the direct dependencies",
+		examples: []string{"gupdeps list -path ./my-project", "gupdeps list -include 'github.com/ourorg/*'"},
+		flags: func(fs *flag.FlagSet, cf *configFlags, _ *options) {
+			cf.registerFilters(fs)
+		},
+		run: func(a *app, _ []string) error {
+			return runList(a.updater, a.logger)
+		},
+	},
+	{
+		name:     "outdated",
+		summary:  "Show the dependencies with newer versions, without analyzing them",
+		examples: []string{"gupdeps outdated -min-release-age 7"},
+		flags: func(fs *flag.FlagSet, cf *configFlags, _ *options) {
+			cf.register(fs)
+			cf.registerFilters(fs)
+		},
+		run: func(a *app, _ []string) error {
+			return runOutdated(a.updater, a.logger)
+		},
+	},
+	{
+		name:    "analyze",
+		args:    "<module[@version]>",
+		nargs:   1,
+		summary: "Analyze the update of one dependency to its latest or a given version",
+		examples: []string{
+			"gupdeps analyze github.com/google/uuid",
+			"gupdeps analyze -format json github.com/google/uuid@v1.6.0",
+		},
+		flags: func(fs *flag.FlagSet, cf *configFlags, _ *options) {
+			cf.register(fs)
+			cf.registerFormat(fs)
+		},
+		run: func(a *app, args []string) error {
+			return runAnalyze(a.updater, a.logger, a.opts, args[0])
+		},
+	},
+	{
+		name:     "explain",
+		args:     "<module>",
+		nargs:    1,
+		summary:  "Explain the decision on a dependency and the policy behind it",
+		examples: []string{"gupdeps explain github.com/google/uuid"},
+		flags: func(fs *flag.FlagSet, cf *configFlags, _ *options) {
+			cf.register(fs)
+		},
+		run: func(a *app, args []string) error {
+			return runExplain(a.updater, a.logger, a.cfg, args[0])
+		},
+	},
+	{
+		name:    "update",
+		summary: "Analyze the dependencies and apply the approved updates (default)",
+		examples: []string{
+			"gupdeps update -path ./my-project",
+			"gupdeps update -interactive -verbose",
+			"gupdeps update -batch -verify \"go build ./...\" -verify \"go test -short ./...\"",
+			"gupdeps update -format markdown > pr-body.md",
+			"gupdeps update -batch -format junit > gupdeps.xml",
+		},
+		flags: func(fs *flag.FlagSet, cf *configFlags, opts *options) {
+			cf.register(fs)
+			cf.registerFilters(fs)
+			cf.registerFormat(fs)
+			cf.registerVerify(fs)
+			fs.BoolVar(&opts.interactive, "interactive", false, "Review and apply every update interactively")
+			fs.BoolVar(&opts.batch, "batch", false, "Apply approved updates as one verified batch, bisecting failures")
+		},
+		run: func(a *app, _ []string) error {
+			if a.opts.interactive {
+				a.logger.Print("🎮 Running in interactive mode...")
+				return runInteractiveMode(a.updater, a.logger)
+			}
+			if a.opts.format == "junit" && !a.opts.batch {
+				// Test cases pass or fail on the verification, which batch mode runs
+				a.logger.Print("🔬 JUnit reports verify the updates, applying them as a batch")
+				a.opts.batch = true
+			}
+			a.logger.Print("🤖 Running in automatic mode...")
+			return runAutomaticMode(a.updater, a.logger, a.opts)
+		},
+	},
+	{
+		name:    "tui",
+		summary: "Review the updates in a full-screen table and apply a selection",
+		examples: []string{
+			"gupdeps tui -path ./my-project",
+			"gupdeps tui -script \"<wait> j <space> x y\"",
+		},
+		flags: func(fs *flag.FlagSet, cf *configFlags, opts *options) {
+			cf.register(fs)
+			cf.registerFilters(fs)
+			fs.StringVar(&opts.script, "script", "", "Replay the keys of a script without a terminal and print the last screen")
+		},
+		run: func(a *app, _ []string) error {
+			return runTUI(a.updater, a.logger, a.opts)
+		},
+	},
+	{
+		name:    "check",
+		summary: "Analyze without modifying files, exit with the most severe failing condition",
+		examples: []string{
+			"gupdeps check -fail-on vulnerable,errors",
+			"gupdeps check -format sarif > gupdeps.sarif",
+		},
+		flags: func(fs *flag.FlagSet, cf *configFlags, _ *options) {
+			cf.register(fs)
+			cf.registerFilters(fs)
+			cf.registerFormat(fs)
+			cf.registerFailOn(fs)
+		},
+		run: func(a *app, _ []string) error {
+			code, err := runCheck(a.updater, a.logger, a.cfg, a.opts)
+			if err == nil && code != 0 {
+				os.Exit(code)
+			}
+			return err
+		},
+	},
+	{
+		name:     "plan",
+		summary:  "Analyze and save the approved updates without applying them",
+		examples: []string{"gupdeps plan -out plan.json"},
+		flags: func(fs *flag.FlagSet, cf *configFlags, opts *options) {
+			cf.register(fs)
+			cf.registerFilters(fs)
+			fs.StringVar(&opts.out, "out", "plan.json", "File the plan is written to")
+		},
+		run: func(a *app, _ []string) error {
+			return runPlan(a.updater, a.logger, a.opts.out)
+		},
+	},
+	{
+		name:     "apply",
+		args:     "<plan.json>",
+		nargs:    1,
+		summary:  "Apply a saved plan, refusing if go.mod changed since",
+		examples: []string{"gupdeps apply -batch plan.json"},
+		flags: func(fs *flag.FlagSet, cf *configFlags, opts *options) {
+			cf.registerVerify(fs)
+			fs.BoolVar(&opts.batch, "batch", false, "Apply the plan as one verified batch, bisecting failures")
+		},
+		run: func(a *app, args []string) error {
+			return runApply(a.updater, a.logger, a.opts, args[0])
+		},
+	},
+	{
+		name:     "undo",
+		summary:  "Revert the last applied update session",
+		examples: []string{"gupdeps undo"},
+		run: func(a *app, _ []string) error {
+			return runUndo(a.updater, a.logger)
+		},
+	},
+	{
+		name:     "config",
+		args:     "validate|print",
+		nargs:    1,
+		summary:  "Check or show the effective configuration",
+		examples: []string{"gupdeps config -max-risk high print"},
+		flags: func(fs *flag.FlagSet, cf *configFlags, _ *options) {
+			cf.register(fs)
+			cf.registerFilters(fs)
+			cf.registerFormat(fs)
+			cf.registerVerify(fs)
+			cf.registerFailOn(fs)
+		},
+		run: func(a *app, args []string) error {
+			return runConfig(a.logger, a.cfg, a.configFile, args)
+		},
+	},
+}
+
+// findCommand returns the command with the given name, nil if there is none
+func findCommand(name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// runCommand parses the arguments of a command line and runs the command,
+// returning the exit code
+func runCommand(args []string) int {
+	name, args := splitCommand(args)
+	if name == "help" {
+		return runHelp(args)
+	}
+
+	cmd := findCommand(name)
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "gupdeps: unknown command %q\n\n", name)
+		printUsage(os.Stderr)
+		return 2
+	}
+
+	fs, cf, opts := cmd.flagSet()
+	if code, ok := cmd.parse(fs, args); !ok {
+		return code
+	}
+
+	a, err := newApp(fs, cf, opts)
+	if err != nil {
+		return 1
+	}
+
+	if err := cmd.run(a, fs.Args()); err != nil {
+		a.logger.Error("%s failed: %v", cmd.name, err)
+		return 1
+	}
+	return 0
+}
+
+// splitCommand returns the command name and its arguments, the default command
+// when the command line starts with a flag
+func splitCommand(args []string) (name string, rest []string) {
+	switch {
+	case len(args) == 0:
+		return defaultCommand, nil
+	case args[0] == "-h" || args[0] == "-help" || args[0] == "--help":
+		return "help", nil
+	case strings.HasPrefix(args[0], "-"):
+		return defaultCommand, args
+	default:
+		return args[0], args[1:]
+	}
+}
+
+// parse parses the flags and checks the number of arguments of a command,
+// returning the exit code when it must not run
+func (c *command) parse(fs *flag.FlagSet, args []string) (int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0, false
+		}
+		return 2, false
+	}
+
+	if c.nargs == 0 && fs.NArg() > 0 && findCommand(fs.Arg(0)) != nil {
+		fmt.Fprintf(fs.Output(), "gupdeps: the command goes before the flags: gupdeps %s [flags]\n", fs.Arg(0))
+		return 2, false
+	}
+
+	if fs.NArg() != c.nargs {
+		fmt.Fprintf(fs.Output(), "gupdeps %s: expected %s\nRun \"gupdeps help %s\" for usage.\n", c.name, c.usageLine(), c.name)
+		return 2, false
+	}
+
+	return 0, true
+}
+
+// newApp loads the configuration and creates the dependency updater for the parsed flags
+func newApp(fs *flag.FlagSet, cf *configFlags, opts *options) (*app, error) {
+	logger := utils.NewLogger(opts.verbose)
+
+	cfg, configFile, err := loadConfig(opts.path, cf, fs)
+	if err != nil {
+		logger.Error("Invalid configuration: %v", err)
+		return nil, err
+	}
+
+	opts.verifyCommands = cfg.Verify
+	opts.project, _ = filepath.Abs(opts.path)
+	opts.format = "text"
+	if fs.Lookup("format") != nil {
+		opts.format = cfg.Format
+	}
+
+	// Reports own stdout, progress goes to stderr
+	if opts.format != "text" {
+		logger.SetOutput(os.Stderr)
+	}
+
+	warnExpiredIgnores(logger, &cfg.Ignore)
+
+	updater, err := newUpdater(opts.path, cfg, logger)
+	if err != nil {
+		logger.Error("%v", err)
+		return nil, err
+	}
+
+	return &app{
+		logger:     logger,
+		cfg:        cfg,
+		configFile: configFile,
+		updater:    updater,
+		opts:       opts,
+	}, nil
+}
+
+// flagSet returns the flags of a command, with the ones every command shares
+func (c *command) flagSet() (*flag.FlagSet, *configFlags, *options) {
+	fs := flag.NewFlagSet("gupdeps "+c.name, flag.ContinueOnError)
+	cf := &configFlags{}
+	opts := &options{}
+
+	fs.StringVar(&opts.path, "path", ".", "Path to the Go project")
+	fs.BoolVar(&opts.verbose, "verbose", false, "Enable verbose logging")
+	fs.StringVar(&cf.configFile, "config", "", "Path to the configuration file (default .gupdeps.yaml in the project)")
+	if c.flags != nil {
+		c.flags(fs, cf, opts)
+	}
+
+	fs.Usage = func() { c.printUsage(fs.Output(), fs) }
+	return fs, cf, opts
+}
+
+// usageLine returns the synopsis of a command
+func (c *command) usageLine() string {
+	line := "gupdeps " + c.name + " [flags]"
+	if c.args != "" {
+		line += " " + c.args
+	}
+	return line
+}
+
+// printUsage prints the help of a command
+func (c *command) printUsage(w io.Writer, fs *flag.FlagSet) {
+	fmt.Fprintf(w, "Usage: %s\n\n%s\n\nFlags:\n", c.usageLine(), c.summary)
+	fs.PrintDefaults()
+
+	if len(c.examples) > 0 {
+		fmt.Fprintln(w, "\nExamples:")
+		for _, example := range c.examples {
+			fmt.Fprintf(w, "  %s\n", example)
+		}
+	}
+}
+
+// printUsage prints the list of commands
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "gupdeps - Analyze and update Go dependencies")
+	fmt.Fprintln(w, "\nUsage:")
+	fmt.Fprintln(w, "  gupdeps <command> [flags] [arguments]")
+	fmt.Fprintln(w, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(w, "  %-10s %s\n", "help", "Show the help of a command")
+	fmt.Fprintf(w, "\nWithout a command gupdeps runs %s. Run \"gupdeps help <command>\" for its flags.\n", defaultCommand)
+	fmt.Fprintln(w, "Flags given on the command line take precedence over the configuration file.")
+}
+
+// runHelp prints the list of commands or the help of one
+func runHelp(args []string) int {
+	if len(args) == 0 {
+		printUsage(os.Stdout)
+		return 0
+	}
+
+	cmd := findCommand(args[0])
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "gupdeps help: unknown command %q\n", args[0])
+		return 2
+	}
+
+	fs, _, _ := cmd.flagSet()
+	fs.SetOutput(os.Stdout)
+	fs.Usage()
+	return 0
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/moeryomenko/gupdeps/internal/config"
+	"github.com/moeryomenko/gupdeps/internal/models"
+	"github.com/moeryomenko/gupdeps/internal/utils"
+	"github.com/moeryomenko/gupdeps/internal/vulndb"
+)
+
+// configFlags holds the flags that make up the analysis configuration
+type configFlags struct {
+	configFile    string
+	rulesFile     string
+	ignoreFile    string
+	maxRisk       string
+	graphGrowth   int
+	vulnDB        string
+	maxGo         string
+	allowLicenses string
+	sizeImpact    bool
+	maxSizeGrowth int
+	concurrency   int
+	format        string
+	failOn        string
+	verify        stringList
+	include       stringList
+	exclude       stringList
+	only          string
+
+	minReleaseAge       int
+	releaseAgeOverrides stringList
+}
+
+// register defines the policy flags shared by all commands
+func (cf *configFlags) register(fs *flag.FlagSet) {
+	defaults := config.Default()
+
+	fs.StringVar(&cf.rulesFile, "rules", "", "Path to a YAML file with commit classification rules")
+	fs.StringVar(&cf.ignoreFile, "ignore", "", "Path to a YAML file listing ignored and pinned updates")
+	fs.StringVar(&cf.maxRisk, "max-risk", string(defaults.Risk.MaxLevel), "Highest risk level approved automatically (low, medium, high, critical)")
+	fs.IntVar(&cf.graphGrowth, "graph-growth", defaults.Risk.GraphGrowth, "Modules an update may add to the module graph before the growth counts as risk")
+	fs.StringVar(&cf.vulnDB, "vulndb", "", "Path or file:// URL of a local Go vulnerability database (default $GOVULNDB)")
+	fs.StringVar(&cf.maxGo, "max-go", "", "Highest Go version an update may require, such as 1.22 (default no limit)")
+	fs.BoolVar(&cf.sizeImpact, "size-impact", false, "Build the main packages to measure the binary size impact of each update")
+	fs.IntVar(&cf.maxSizeGrowth, "max-size-growth", defaults.Size.MaxGrowth, "Binary growth in percent above which an update is flagged")
+	fs.IntVar(&cf.minReleaseAge, "min-release-age", 0, "Days a release must be published before it is proposed")
+	fs.Var(&cf.releaseAgeOverrides, "release-age-override", "Minimum release age of matching modules as pattern=days (repeatable)")
+	fs.StringVar(&cf.allowLicenses, "allow-licenses", "", "Comma-separated SPDX identifiers dependencies may be updated to (default permissive licenses)")
+	fs.IntVar(&cf.concurrency, "concurrency", defaults.Concurrency, "Number of dependencies analyzed in parallel")
+}
+
+// registerFilters defines the flags selecting the dependencies to work on
+func (cf *configFlags) registerFilters(fs *flag.FlagSet) {
+	fs.Var(&cf.include, "include", "Only work on modules matching a glob or /regexp/ (repeatable)")
+	fs.Var(&cf.exclude, "exclude", "Skip modules matching a glob or /regexp/ (repeatable)")
+	fs.StringVar(&cf.only, "only", "", "Only work on the given module, ignoring -include and -exclude")
+}
+
+// registerFormat defines the report format flag
+func (cf *configFlags) registerFormat(fs *flag.FlagSet) {
+	fs.StringVar(&cf.format, "format", config.Default().Format, "Output format: "+strings.Join(config.Formats, ", "))
+}
+
+// registerVerify defines the verification command flag
+func (cf *configFlags) registerVerify(fs *flag.FlagSet) {
+	fs.Var(&cf.verify, "verify", "Verification shell command for batch mode (repeatable, default \"go build ./...\" and \"go test ./...\")")
+}
+
+// registerFailOn defines the flag selecting the conditions failing a check
+func (cf *configFlags) registerFailOn(fs *flag.FlagSet) {
+	fs.StringVar(&cf.failOn, "fail-on", strings.Join(config.Default().Check.FailOn, ","),
+		"Comma-separated conditions failing the check: "+strings.Join(config.CheckConditions, ", "))
+}
+
+// loadConfig builds the effective configuration: the built-in defaults, then the
+// configuration file, then the flags given on the command line. It also returns
+// the configuration file used, if any.
+func loadConfig(projectPath string, cf *configFlags, fs *flag.FlagSet) (*config.Config, string, error) {
+	file := cf.configFile
+	if file == "" {
+		file = config.Discover(projectPath)
+	}
+
+	cfg := config.Default()
+	if file != "" {
+		var err error
+		if cfg, err = config.Load(file); err != nil {
+			return nil, "", err
+		}
+	}
+
+	// Only flags given explicitly take precedence over the file
+	setters := cf.setters(cfg)
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if set, ok := setters[f.Name]; ok && err == nil {
+			err = set()
+		}
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	if cfg.VulnDB, err = vulndb.Locate(cfg.VulnDB); err != nil {
+		return nil, "", err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	return cfg, file, nil
+}
+
+// setters returns, for every configuration flag, a function applying its value
+func (cf *configFlags) setters(cfg *config.Config) map[string]func() error {
+	return map[string]func() error{
+		"rules": func() (err error) {
+			cfg.Rules, err = config.LoadRules(cf.rulesFile)
+			return err
+		},
+		"ignore": func() (err error) {
+			cfg.Ignore, err = config.LoadIgnore(cf.ignoreFile)
+			return err
+		},
+		"allow-licenses": func() (err error) {
+			cfg.Licenses, err = config.ParseLicenses(cf.allowLicenses)
+			return err
+		},
+		"fail-on": func() (err error) {
+			cfg.Check, err = config.ParseFailOn(cf.failOn)
+			return err
+		},
+		"release-age-override": func() error {
+			for _, value := range cf.releaseAgeOverrides {
+				override, err := config.ParseCooldownOverride(value)
+				if err != nil {
+					return err
+				}
+				cfg.Cooldown.Overrides = append(cfg.Cooldown.Overrides, override)
+			}
+			return nil
+		},
+		"only":            func() error { cfg.Only = cf.only; return nil },
+		"max-risk":        func() error { cfg.Risk.MaxLevel = models.RiskLevel(cf.maxRisk); return nil },
+		"graph-growth":    func() error { cfg.Risk.GraphGrowth = cf.graphGrowth; return nil },
+		"vulndb":          func() error { cfg.VulnDB = cf.vulnDB; return nil },
+		"max-go":          func() error { cfg.Go.MaxVersion = cf.maxGo; return nil },
+		"size-impact":     func() error { cfg.Size.Enabled = cf.sizeImpact; return nil },
+		"max-size-growth": func() error { cfg.Size.MaxGrowth = cf.maxSizeGrowth; return nil },
+		"min-release-age": func() error { cfg.Cooldown.Days = cf.minReleaseAge; return nil },
+		"concurrency":     func() error { cfg.Concurrency = cf.concurrency; return nil },
+		"verify":          func() error { cfg.Verify = cf.verify; return nil },
+		"include":         func() error { cfg.Include = cf.include; return nil },
+		"exclude":         func() error { cfg.Exclude = cf.exclude; return nil },
+		"format":          func() error { cfg.Format = cf.format; return nil },
+	}
+}
+
+// runConfig validates or prints the effective configuration
+func runConfig(logger *utils.Logger, cfg *config.Config, file string, args []string) error {
+	if len(args) != 1 {
+		return errors.New("expected validate or print")
+	}
+	action := args[0]
+
+	source := file
+	if source == "" {
+		source = "built-in defaults"
+	}
+
+	switch action {
+	case "validate":
+		logger.Success("Configuration is valid (%s)", source)
+		return nil
+	case "print":
+		fmt.Printf("# Effective configuration from %s and flags\n", source)
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(cfg); err != nil {
+			return fmt.Errorf("failed to print configuration: %w", err)
+		}
+		return encoder.Close()
+	default:
+		return fmt.Errorf("unknown config command %q, expected validate or print", action)
+	}
+}
+
+// warnExpiredIgnores reports ignore entries past their expiry date so stale pins get revisited
+func warnExpiredIgnores(logger *utils.Logger, ignore *config.IgnoreConfig) {
+	expired := ignore.Expired(time.Now())
+	for i := range expired {
+		entry := &expired[i]
+		logger.Warn("Ignore entry for %s expired on %s, revisit it: %s", entry.Module, entry.Expires, entry.Describe())
+	}
+}
+
+// stringList is a flag value that collects repeated occurrences of a flag
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ", ")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
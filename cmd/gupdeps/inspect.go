@@ -0,0 +1,201 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/moeryomenko/gupdeps/internal/config"
+	"github.com/moeryomenko/gupdeps/internal/dependencies"
+	"github.com/moeryomenko/gupdeps/internal/models"
+	"github.com/moeryomenko/gupdeps/internal/report"
+	"github.com/moeryomenko/gupdeps/internal/utils"
+)
+
+// runList prints the direct dependencies selected by the include and exclude patterns
+func runList(updater *dependencies.DependencyUpdater, logger *utils.Logger) error {
+	deps, err := updater.GetAllDependencies()
+	if err != nil {
+		return err
+	}
+
+	for _, dep := range deps {
+		logger.Print("%s %s", dep.Name, dep.CurrentVersion)
+	}
+	return nil
+}
+
+// runOutdated prints the dependencies with a newer version, without analyzing the updates
+func runOutdated(updater *dependencies.DependencyUpdater, logger *utils.Logger) error {
+	deps, err := updater.GetAllDependencies()
+	if err != nil {
+		return err
+	}
+
+	logger.Print("📡 Checking %d direct dependencies for updates...", len(deps))
+	errs := updater.FindUpdates(deps)
+
+	outdated := 0
+	for i, dep := range deps {
+		if errs[i] != nil {
+			logger.Warn("Could not check %s: %v", dep.Name, errs[i])
+			continue
+		}
+		if !dep.UpdateNeeded {
+			continue
+		}
+
+		if outdated == 0 {
+			logger.Print("\n📦 Outdated Dependencies:")
+		}
+		outdated++
+		logger.Print("  %s %s → %s (%s)",
+			dep.Name, dep.CurrentVersion, dep.LatestVersion, dependencies.BumpKind(dep.CurrentVersion, dep.LatestVersion))
+	}
+
+	displayPendingUpdates(logger, deps)
+	displayIgnoredUpdates(logger, deps)
+
+	if outdated == 0 {
+		logger.Success("All dependencies are up to date")
+	}
+	return nil
+}
+
+// runAnalyze analyzes the update of a single dependency, to its latest version
+// or to the version given as module@version
+func runAnalyze(updater *dependencies.DependencyUpdater, logger *utils.Logger, opts *options, target string) error {
+	module, version, _ := strings.Cut(target, "@")
+	dep, err := updater.GetDependency(module)
+	if err != nil {
+		return err
+	}
+
+	var analysis *models.UpdateAnalysis
+	if version == "" {
+		analysis, err = updater.AnalyzeDependency(dep)
+	} else {
+		if !strings.HasPrefix(version, "v") {
+			version = "v" + version
+		}
+		analysis, err = updater.AnalyzeTarget(dep, version)
+	}
+	if err != nil {
+		return err
+	}
+
+	if opts.format != "text" {
+		rep := report.New(opts.project, []*models.Dependency{dep}, []*models.UpdateAnalysis{analysis}, []error{nil})
+		return report.Write(os.Stdout, opts.format, rep)
+	}
+
+	if dep.UpdateNeeded {
+		displayDependencyInfo(logger, dep, analysis)
+		return nil
+	}
+
+	displayHeldBackDependency(logger, dep)
+	if dep.Pending == nil && dep.Ignored == nil {
+		logger.Success("%s is up to date at %s", dep.Name, dep.CurrentVersion)
+	}
+	displayVulnerabilities(logger, analysis.Vulnerabilities)
+	return nil
+}
+
+// runExplain prints the decision on a dependency with the policy and the
+// findings it was made from
+func runExplain(updater *dependencies.DependencyUpdater, logger *utils.Logger, cfg *config.Config, module string) error {
+	dep, err := updater.GetDependency(module)
+	if err != nil {
+		return err
+	}
+
+	analysis, err := updater.AnalyzeDependency(dep)
+	if err != nil {
+		return err
+	}
+
+	logger.Print("\n📦 %s %s", dep.Name, dep.CurrentVersion)
+	if !cfg.Selects(dep.Name) {
+		logger.Print("Skipped by the include and exclude patterns in other commands")
+	}
+	explainDecision(logger, dep, analysis)
+	explainPolicy(logger, cfg, dep.Name)
+
+	if dep.UpdateNeeded {
+		explainCommits(logger, cfg.Rules.For(dep.Name), analysis)
+		displayLicense(logger, analysis.License)
+		displayGoRequirement(logger, analysis.GoRequirement)
+		displayRisk(logger, analysis.Risk)
+	}
+	displayVulnerabilities(logger, analysis.Vulnerabilities)
+
+	return nil
+}
+
+// explainDecision prints whether a dependency is updated and why
+func explainDecision(logger *utils.Logger, dep *models.Dependency, analysis *models.UpdateAnalysis) {
+	switch {
+	case dep.UpdateNeeded && analysis.ShouldUpdate:
+		logger.Print("Decision: ✅ update to %s: %s", dep.LatestVersion, analysis.UpdateReason)
+	case dep.UpdateNeeded:
+		logger.Print("Decision: ❌ keep, update to %s rejected: %s", dep.LatestVersion, analysis.RejectionReason)
+	case dep.Pending != nil || dep.Ignored != nil:
+		logger.Print("Decision: ⏸️  keep, newer releases are held back")
+	default:
+		logger.Print("Decision: ✅ up to date")
+	}
+
+	if dep.Pending != nil {
+		logger.Print("⏳ %s", formatPending(dep.Pending))
+	}
+	if dep.Ignored != nil {
+		logger.Print("🙈 %s", formatIgnored(dep.Ignored))
+	}
+	if len(dep.Retracted) > 0 {
+		logger.Print("⚠️  %s is retracted: %s", dep.CurrentVersion, strings.Join(dep.Retracted, "; "))
+	}
+}
+
+// explainPolicy prints the policy in effect for a module
+func explainPolicy(logger *utils.Logger, cfg *config.Config, module string) {
+	policy := cfg.ForModule(module)
+
+	maxGo := policy.Go.MaxVersion
+	if maxGo == "" {
+		maxGo = "go directive of the project"
+	}
+
+	logger.Print("\nPolicy:")
+	logger.Print("  Max risk:         %s", policy.Risk.MaxLevel)
+	logger.Print("  Max Go:           %s", maxGo)
+	logger.Print("  Licenses:         %s", strings.Join(policy.Licenses.Allowed, ", "))
+	logger.Print("  Min release age:  %d days", cfg.Cooldown.For(module))
+
+	for i := range cfg.Modules {
+		if cfg.Modules[i].Matches(module) {
+			logger.Print("  Override:         %s", cfg.Modules[i].Module)
+		}
+	}
+}
+
+// explainCommits prints how the commits of an update were classified and what
+// every category contributes to the decision
+func explainCommits(logger *utils.Logger, categories []config.Category, analysis *models.UpdateAnalysis) {
+	counts := make(map[string]int)
+	for i := range analysis.Commits {
+		counts[analysis.Commits[i].Category]++
+	}
+
+	logger.Print("\nCommit classification (%d commits):", len(analysis.Commits))
+	for i := range categories {
+		category := &categories[i]
+		logger.Print("  %-14s %3d × %+d  %s", category.Name, counts[category.Name], category.Weight, category.Decision)
+	}
+	if unclassified := counts[""]; unclassified > 0 {
+		logger.Print("  %-14s %3d", "unclassified", unclassified)
+	}
+
+	if changelog := analysis.Changelog; changelog != nil && len(changelog.Sections) > 0 {
+		logger.Print("Changelog entries from %s take precedence over commits when classified", changelog.Source)
+	}
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moeryomenko/gupdeps/internal/dependencies"
+	"github.com/moeryomenko/gupdeps/internal/models"
+	"github.com/moeryomenko/gupdeps/internal/utils"
+)
+
+// defaultSnoozeDays is how long the snooze action postpones a module unless told otherwise
+const defaultSnoozeDays = 14
+
+// interactiveHelp describes the actions of the interactive prompt
+var interactiveHelp = []string{
+	"y      apply this update",
+	"n      skip this update",
+	"a      apply this and all remaining approved updates",
+	"d      show all commits",
+	"v <n>  show the diff of commit n in the module directory",
+	"c      show the changelog and release notes",
+	"p      pick another target version",
+	"s [d]  snooze the module for d days (default 14)",
+	"q      quit, keeping the updates applied so far",
+	"?      show this help",
+}
+
+// prompt drives the interactive review of the dependency updates
+type prompt struct {
+	updater  *dependencies.DependencyUpdater
+	logger   *utils.Logger
+	session  *dependencies.Session
+	reader   *bufio.Reader
+	applyAll bool // apply the remaining approved updates without asking
+	quit     bool
+}
+
+// review is the dependency update shown at the prompt
+type review struct {
+	dep      *models.Dependency
+	analysis *models.UpdateAnalysis
+}
+
+// promptAction handles an action typed at the prompt and reports whether the
+// review of the update is over
+type promptAction func(p *prompt, r *review, arg string) (bool, error)
+
+// promptActions maps the commands of the prompt to their actions
+var promptActions = map[string]promptAction{
+	"":     actionSkip,
+	"y":    actionApply,
+	"yes":  actionApply,
+	"n":    actionSkip,
+	"no":   actionSkip,
+	"a":    actionApplyAll,
+	"d":    actionCommits,
+	"v":    actionDiff,
+	"c":    actionChangelog,
+	"p":    actionPick,
+	"s":    actionSnooze,
+	"q":    actionQuit,
+	"quit": actionQuit,
+	"?":    actionHelp,
+}
+
+func runInteractiveMode(updater *dependencies.DependencyUpdater, logger *utils.Logger) error {
+	deps, err := updater.GetAllDependencies()
+	if err != nil {
+		return err
+	}
+
+	session, err := updater.BeginSession()
+	if err != nil {
+		return fmt.Errorf("failed to start update session: %w", err)
+	}
+
+	// Diffs of commits come from the clones of the analyses
+	release := updater.KeepClones()
+	defer release()
+
+	p := &prompt{
+		updater: updater,
+		logger:  logger,
+		session: session,
+		reader:  bufio.NewReader(os.Stdin),
+	}
+
+	for _, dep := range deps {
+		if err := p.process(dep); err != nil {
+			return err
+		}
+		if p.quit {
+			break
+		}
+	}
+
+	if session.Applied() == 0 {
+		return nil
+	}
+
+	// Run go mod tidy at the end of the session
+	logger.Print("\n🧹 Running go mod tidy...")
+	if err := session.Tidy(); err != nil {
+		return err
+	}
+
+	return session.Commit()
+}
+
+// process analyzes a dependency and reviews its update, if any
+func (p *prompt) process(dep *models.Dependency) error {
+	analysis, err := p.updater.AnalyzeDependency(dep)
+	if err != nil {
+		p.logger.Warn("Could not analyze %s: %v", dep.Name, err)
+		return nil
+	}
+
+	if !dep.UpdateNeeded {
+		displayHeldBackDependency(p.logger, dep)
+		return nil
+	}
+
+	if p.applyAll {
+		return p.applyApproved(dep, analysis)
+	}
+
+	displayDependencyInfo(p.logger, dep, analysis)
+	return p.review(&review{dep: dep, analysis: analysis})
+}
+
+// review asks what to do with a dependency update until it is applied, skipped or snoozed
+func (p *prompt) review(r *review) error {
+	for {
+		p.logger.Print("\nApply this update? (y/n/a/d/v/c/p/s/q/?): ")
+		line, err := p.reader.ReadString('\n')
+		if err != nil && line == "" {
+			// End of input quits rather than skipping every remaining update
+			p.quit = true
+			return nil
+		}
+
+		name, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+		action, ok := promptActions[strings.ToLower(name)]
+		if !ok {
+			p.logger.Print("Unknown action %q, ? shows the available actions", name)
+			continue
+		}
+
+		done, err := action(p, r, strings.TrimSpace(arg))
+		if err != nil || done {
+			return err
+		}
+	}
+}
+
+// applyApproved applies an update without asking if the analysis approves it
+func (p *prompt) applyApproved(dep *models.Dependency, analysis *models.UpdateAnalysis) error {
+	if !analysis.ShouldUpdate {
+		p.logger.Print("\n❌ %s (%s → %s) not applied: %s",
+			dep.Name, dep.CurrentVersion, dep.LatestVersion, analysis.RejectionReason)
+		return nil
+	}
+
+	if err := p.session.Apply(dep); err != nil {
+		return fmt.Errorf("failed to update %s: %w", dep.Name, err)
+	}
+	return nil
+}
+
+func actionApply(p *prompt, r *review, _ string) (bool, error) {
+	if err := p.session.Apply(r.dep); err != nil {
+		return false, fmt.Errorf("failed to update %s: %w", r.dep.Name, err)
+	}
+	return true, nil
+}
+
+func actionSkip(p *prompt, _ *review, _ string) (bool, error) {
+	p.logger.Print("⏭️  Skipped")
+	return true, nil
+}
+
+func actionApplyAll(p *prompt, r *review, arg string) (bool, error) {
+	p.applyAll = true
+	return actionApply(p, r, arg)
+}
+
+func actionQuit(p *prompt, _ *review, _ string) (bool, error) {
+	p.quit = true
+	return true, nil
+}
+
+func actionHelp(p *prompt, _ *review, _ string) (bool, error) {
+	for _, line := range interactiveHelp {
+		p.logger.Print("  %s", line)
+	}
+	return false, nil
+}
+
+// actionCommits lists every commit of the update, numbered for the diff action
+func actionCommits(p *prompt, r *review, _ string) (bool, error) {
+	commits := r.analysis.Commits
+	if len(commits) == 0 {
+		p.logger.Print("No commits found")
+		return false, nil
+	}
+
+	p.logger.Print("Commits (%d):", len(commits))
+	for i := range commits {
+		commit := &commits[i]
+		p.logger.Print("  %3d. %s %s %s",
+			i+1, commit.Hash[:min(7, len(commit.Hash))], commit.Date.Format(time.DateOnly), formatCommit(*commit))
+	}
+	return false, nil
+}
+
+// actionDiff shows the changes a commit made to the module
+func actionDiff(p *prompt, r *review, arg string) (bool, error) {
+	commits := r.analysis.Commits
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 || n > len(commits) {
+		p.logger.Print("Expected a commit number between 1 and %d: v <n>", len(commits))
+		return false, nil
+	}
+
+	commit := &commits[n-1]
+	diff, err := p.updater.GetCommitDiff(r.dep, commit.Hash)
+	if err != nil {
+		p.logger.Warn("Could not show commit %s: %v", commit.Hash, err)
+		return false, nil
+	}
+
+	p.logger.Print("%s", strings.TrimRight(diff, "\n"))
+	return false, nil
+}
+
+// actionChangelog shows the changelog entries and annotated tag messages of the update
+func actionChangelog(p *prompt, r *review, _ string) (bool, error) {
+	changelog := r.analysis.Changelog
+	if changelog == nil || (len(changelog.Sections) == 0 && len(changelog.TagNotes) == 0) {
+		p.logger.Print("No changelog or release notes found")
+		return false, nil
+	}
+
+	displayChangelog(p.logger, changelog)
+	for _, note := range changelog.TagNotes {
+		p.logger.Print("Release notes of %s:", note.Tag)
+		for _, line := range strings.Split(note.Message, "\n") {
+			p.logger.Print("  %s", line)
+		}
+	}
+	return false, nil
+}
+
+// actionPick analyzes the update to another of the newer versions
+func actionPick(p *prompt, r *review, _ string) (bool, error) {
+	versions := r.dep.Versions
+	if len(versions) == 0 {
+		p.logger.Print("No other versions available")
+		return false, nil
+	}
+
+	p.logger.Print("Available versions:")
+	for i, version := range versions {
+		marker := ""
+		if version == r.dep.LatestVersion {
+			marker = " (proposed)"
+		}
+		p.logger.Print("  %2d. %s%s", i+1, version, marker)
+	}
+
+	p.logger.Print("Pick a version (1-%d): ", len(versions))
+	line, _ := p.reader.ReadString('\n')
+	n, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || n < 1 || n > len(versions) {
+		p.logger.Print("Keeping %s", r.dep.LatestVersion)
+		return false, nil
+	}
+
+	previous := r.dep.LatestVersion
+	analysis, err := p.updater.AnalyzeTarget(r.dep, versions[n-1])
+	if err != nil {
+		r.dep.LatestVersion = previous
+		p.logger.Warn("Could not analyze %s@%s: %v", r.dep.Name, versions[n-1], err)
+		return false, nil
+	}
+
+	r.analysis = analysis
+	displayDependencyInfo(p.logger, r.dep, analysis)
+	return false, nil
+}
+
+// actionSnooze postpones the updates of the module for some days
+func actionSnooze(p *prompt, r *review, arg string) (bool, error) {
+	days := defaultSnoozeDays
+	if arg != "" {
+		var err error
+		if days, err = strconv.Atoi(arg); err != nil || days < 1 {
+			p.logger.Print("Expected a number of days: s [days]")
+			return false, nil
+		}
+	}
+
+	until, err := p.updater.Snooze(r.dep.Name, days)
+	if err != nil {
+		return false, err
+	}
+
+	p.logger.Print("😴 %s snoozed until %s", r.dep.Name, until.Format(time.DateOnly))
+	return true, nil
+}
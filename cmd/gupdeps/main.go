@@ -1,64 +1,38 @@
 package main
 
 import (
-	"bufio"
-	"flag"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/moeryomenko/gupdeps/internal/config"
 	"github.com/moeryomenko/gupdeps/internal/dependencies"
 	"github.com/moeryomenko/gupdeps/internal/models"
+	"github.com/moeryomenko/gupdeps/internal/report"
 	"github.com/moeryomenko/gupdeps/internal/utils"
+	"github.com/moeryomenko/gupdeps/internal/vulndb"
 )
 
 func main() {
-	// Parse command-line flags
-	projectPath := flag.String("path", ".", "Path to the Go project")
-	interactive := flag.Bool("interactive", false, "Run in interactive mode")
-	verbose := flag.Bool("verbose", false, "Enable verbose logging")
-	help := flag.Bool("help", false, "Show help information")
-
-	flag.Parse()
-
-	if *help {
-		printHelp()
-		os.Exit(0)
-	}
-
-	// Initialize logger
-	logger := utils.NewLogger(*verbose)
+	os.Exit(runCommand(os.Args[1:]))
+}
 
-	// Create dependency updater
-	updater := dependencies.NewDependencyUpdater(*projectPath, logger)
+// newUpdater creates the dependency updater with the resources the configuration asks for
+func newUpdater(projectPath string, cfg *config.Config, logger *utils.Logger) (*dependencies.DependencyUpdater, error) {
+	updater := dependencies.NewDependencyUpdater(projectPath, cfg, logger)
 
-	if *interactive {
-		logger.Print("🎮 Running in interactive mode...")
-		if err := runInteractiveMode(updater, logger); err != nil {
-			logger.Error("Interactive mode failed: %v", err)
-			os.Exit(1)
-		}
-	} else {
-		logger.Print("🤖 Running in automatic mode...")
-		if err := runAutomaticMode(updater, logger); err != nil {
-			logger.Error("Update failed: %v", err)
-			os.Exit(1)
+	if cfg.VulnDB != "" {
+		db, err := vulndb.Open(cfg.VulnDB)
+		if err != nil {
+			return nil, err
 		}
+		updater.SetVulnDB(db)
 	}
-}
 
-func printHelp() {
-	fmt.Println("Dependency Updater - Analyze and update Go dependencies")
-	fmt.Println("\nUsage:")
-	fmt.Println("  update-deps [flags]")
-	fmt.Println("\nFlags:")
-	fmt.Println("  -path string        Path to the Go project (default \".\")")
-	fmt.Println("  -interactive        Run in interactive mode")
-	fmt.Println("  -verbose            Enable verbose logging")
-	fmt.Println("  -help               Show this help information")
-	fmt.Println("\nExamples:")
-	fmt.Println("  update-deps -path ./my-project")
-	fmt.Println("  update-deps -interactive -verbose")
+	return updater, nil
 }
 
 // fetchAndDisplayDependencies gets dependencies and displays them
@@ -86,28 +60,47 @@ func fetchAndDisplayDependencies(
 	return deps, nil
 }
 
+// analysisResults holds the analyses of all dependencies, indexed like them, and
+// the updates they approve or reject
+type analysisResults struct {
+	analyses []*models.UpdateAnalysis
+	errs     []error
+	approved []*models.UpdateAnalysis
+	rejected []*models.UpdateAnalysis
+}
+
 // analyzeDependencies analyzes each dependency and returns approved/rejected updates
-func analyzeDependencies(updater *dependencies.DependencyUpdater, logger *utils.Logger, deps []*models.Dependency) (
-	approvedList, rejectedList []*models.UpdateAnalysis, err error,
-) {
+func analyzeDependencies(
+	updater *dependencies.DependencyUpdater,
+	logger *utils.Logger,
+	deps []*models.Dependency,
+) (*analysisResults, error) {
 	logger.Print("📡 Checking for updates...")
 	var approvedUpdates []*models.UpdateAnalysis
 	var rejectedUpdates []*models.UpdateAnalysis
 
-	for _, dep := range deps {
-		logger.Print("🔍 Analyzing %s (%s)...", dep.Name, dep.CurrentVersion)
+	analyses, errs := updater.AnalyzeDependencies(deps)
+	for i, dep := range deps {
+		logger.Print("🔍 %s (%s)", dep.Name, dep.CurrentVersion)
 
-		analysis, err := updater.AnalyzeDependency(dep)
-		if err != nil {
-			logger.Warn("Could not analyze %s: %v", dep.Name, err)
+		analysis := analyses[i]
+		if errs[i] != nil {
+			logger.Warn("Could not analyze %s: %v", dep.Name, errs[i])
 			continue
 		}
 
+		if dep.Pending != nil {
+			logger.Print("  ⏳ %s", formatPending(dep.Pending))
+		}
+		if dep.Ignored != nil {
+			logger.Print("  🙈 %s", formatIgnored(dep.Ignored))
+		}
+
 		if !dep.UpdateNeeded {
 			continue
 		}
 
-		logger.Print("  Current: %s → Latest: %s", dep.CurrentVersion, dep.LatestVersion)
+		displayAnalysisSummary(logger, analysis)
 
 		if analysis.ShouldUpdate {
 			approvedUpdates = append(approvedUpdates, analysis)
@@ -118,7 +111,17 @@ func analyzeDependencies(updater *dependencies.DependencyUpdater, logger *utils.
 		}
 	}
 
-	return approvedUpdates, rejectedUpdates, nil
+	// Updates fixing reachable vulnerabilities go first
+	sort.SliceStable(approvedUpdates, func(i, j int) bool {
+		return approvedUpdates[i].Priority > approvedUpdates[j].Priority
+	})
+
+	return &analysisResults{
+		analyses: analyses,
+		errs:     errs,
+		approved: approvedUpdates,
+		rejected: rejectedUpdates,
+	}, nil
 }
 
 // applyUpdates applies the approved updates
@@ -131,20 +134,104 @@ func applyUpdates(
 		return nil
 	}
 
+	session, err := updater.BeginSession()
+	if err != nil {
+		return fmt.Errorf("failed to start update session: %w", err)
+	}
+
 	logger.Print("🚀 Applying approved updates...")
-	for _, analysis := range approvedUpdates {
-		if err := updater.ApplyUpdate(analysis.Dependency); err != nil {
-			logger.Error("Failed to update %s: %v", analysis.Dependency.Name, err)
+	for i, analysis := range approvedUpdates {
+		if err := session.Apply(analysis.Dependency); err != nil {
+			// The session rolled back the updates applied before this one
+			for _, applied := range approvedUpdates[:i] {
+				applied.Outcome = nil
+			}
+			analysis.Outcome = &models.UpdateOutcome{Output: err.Error()}
+			return fmt.Errorf("failed to update %s: %w", analysis.Dependency.Name, err)
 		}
+		analysis.Outcome = &models.UpdateOutcome{Applied: true}
 	}
 
 	// Run go mod tidy to clean up
 	logger.Print("\n🧹 Running go mod tidy...")
-	if err := updater.RunModTidy(); err != nil {
-		logger.Warn("go mod tidy failed: %v", err)
+	if err := session.Tidy(); err != nil {
+		return err
 	}
 
-	return nil
+	return session.Commit()
+}
+
+// displayAnalysisSummary prints the key figures of an update analysis
+func displayAnalysisSummary(logger *utils.Logger, analysis *models.UpdateAnalysis) {
+	dep := analysis.Dependency
+	logger.Print("  Current: %s → Latest: %s", dep.CurrentVersion, dep.LatestVersion)
+	if analysis.Risk != nil {
+		logger.Print("  Risk: %d (%s)", analysis.Risk.Score, analysis.Risk.Level)
+	}
+	if analysis.SizeImpact != nil {
+		logger.Print("  Binary size: %s", dependencies.FormatSizeImpact(analysis.SizeImpact))
+	}
+	if license := analysis.License; license != nil && license.From != license.To {
+		logger.Print("  License: %s → %s", dependencies.LicenseName(license.From), dependencies.LicenseName(license.To))
+	}
+}
+
+// displayPendingUpdates prints the releases held back by the minimum release age
+func displayPendingUpdates(logger *utils.Logger, deps []*models.Dependency) {
+	var pending []*models.Dependency
+	for _, dep := range deps {
+		if dep.Pending != nil {
+			pending = append(pending, dep)
+		}
+	}
+
+	if len(pending) == 0 {
+		return
+	}
+
+	logger.Print("\n⏳ Pending Updates (minimum release age not reached):")
+	for _, dep := range pending {
+		logger.Print("  %s (%s): %s", dep.Name, dep.CurrentVersion, formatPending(dep.Pending))
+	}
+}
+
+// displayHeldBackDependency prints a dependency whose updates are all pending or ignored
+func displayHeldBackDependency(logger *utils.Logger, dep *models.Dependency) {
+	if dep.Pending != nil {
+		logger.Print("\n⏳ %s %s", dep.Name, formatPending(dep.Pending))
+	}
+	if dep.Ignored != nil {
+		logger.Print("\n🙈 %s %s", dep.Name, formatIgnored(dep.Ignored))
+	}
+}
+
+// displayIgnoredUpdates prints the releases skipped by the ignore list
+func displayIgnoredUpdates(logger *utils.Logger, deps []*models.Dependency) {
+	var ignored []*models.Dependency
+	for _, dep := range deps {
+		if dep.Ignored != nil {
+			ignored = append(ignored, dep)
+		}
+	}
+
+	if len(ignored) == 0 {
+		return
+	}
+
+	logger.Print("\n🙈 Ignored Updates:")
+	for _, dep := range ignored {
+		logger.Print("  %s (%s): %s", dep.Name, dep.CurrentVersion, formatIgnored(dep.Ignored))
+	}
+}
+
+// formatIgnored describes a release skipped by the ignore list
+func formatIgnored(ignored *models.IgnoredVersion) string {
+	return ignored.Version + " ignored: " + ignored.Reason
+}
+
+// formatPending describes a release held back by the minimum release age
+func formatPending(pending *models.PendingVersion) string {
+	return pending.Version + " pending until " + pending.Until.Format(time.DateOnly)
 }
 
 // displayRejectedUpdates prints info about rejected updates
@@ -163,16 +250,68 @@ func displayRejectedUpdates(logger *utils.Logger, rejectedUpdates []*models.Upda
 	}
 }
 
-func runAutomaticMode(updater *dependencies.DependencyUpdater, logger *utils.Logger) error {
+// applyBatch applies the approved updates as one verified batch and reports culprits
+func applyBatch(
+	updater *dependencies.DependencyUpdater,
+	logger *utils.Logger,
+	approvedUpdates []*models.UpdateAnalysis,
+	verifyCommands []string,
+) error {
+	if len(approvedUpdates) == 0 {
+		return nil
+	}
+
+	session, err := updater.BeginSession()
+	if err != nil {
+		return fmt.Errorf("failed to start update session: %w", err)
+	}
+
+	logger.Print("🚀 Applying %d approved updates as a batch...", len(approvedUpdates))
+	result, err := session.ApplyBatch(approvedUpdates, verifyCommands)
+	if err != nil {
+		return err
+	}
+
+	logger.Print("\n📋 Batch Summary:")
+	logger.Print("  Applied: %d updates", len(result.Applied))
+	logger.Print("  Failed:  %d updates", len(result.Culprits))
+
+	for _, culprit := range result.Culprits {
+		logger.Print("\n💥 %s (%s → %s) breaks verification:",
+			culprit.Dependency.Name,
+			culprit.Dependency.CurrentVersion,
+			culprit.Dependency.LatestVersion)
+		for _, line := range lastLines(culprit.Outcome.Output, culpritOutputLines) {
+			logger.Print("    %s", line)
+		}
+	}
+
+	return session.Commit()
+}
+
+// culpritOutputLines limits how much verification output is shown per culprit
+const culpritOutputLines = 20
+
+// lastLines returns at most n trailing lines of the output
+func lastLines(output string, n int) []string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+func runAutomaticMode(updater *dependencies.DependencyUpdater, logger *utils.Logger, opts *options) error {
 	deps, err := fetchAndDisplayDependencies(updater, logger)
 	if err != nil {
 		return err
 	}
 
-	approvedUpdates, rejectedUpdates, err := analyzeDependencies(updater, logger, deps)
+	results, err := analyzeDependencies(updater, logger, deps)
 	if err != nil {
 		return err
 	}
+	approvedUpdates, rejectedUpdates := results.approved, results.rejected
 
 	// Display summary
 	logger.Print("\n📋 Update Summary:")
@@ -180,19 +319,39 @@ func runAutomaticMode(updater *dependencies.DependencyUpdater, logger *utils.Log
 	logger.Print("  Rejected: %d updates", len(rejectedUpdates))
 	logger.Print("")
 
-	if err := applyUpdates(updater, logger, approvedUpdates); err != nil {
-		return err
+	if opts.batch {
+		err = applyBatch(updater, logger, approvedUpdates, opts.verifyCommands)
+	} else {
+		err = applyUpdates(updater, logger, approvedUpdates)
+	}
+	if err == nil {
+		displayRejectedUpdates(logger, rejectedUpdates)
+		displayPendingUpdates(logger, deps)
+		displayIgnoredUpdates(logger, deps)
 	}
 
-	displayRejectedUpdates(logger, rejectedUpdates)
+	if opts.format == "text" {
+		return err
+	}
 
-	return nil
+	// The report is written when applying fails as well, CI needs it most then
+	rep := report.New(opts.project, deps, results.analyses, results.errs)
+	return errors.Join(err, report.Write(os.Stdout, opts.format, rep))
 }
 
+// recentCommitLimit caps the commits shown with the details of an update
+const recentCommitLimit = 5
+
 // displayDependencyInfo shows detailed information about a dependency update
 func displayDependencyInfo(logger *utils.Logger, dep *models.Dependency, analysis *models.UpdateAnalysis) {
 	logger.Print("\n📦 %s", dep.Name)
 	logger.Print("Current: %s → Latest: %s", dep.CurrentVersion, dep.LatestVersion)
+	if dep.Pending != nil {
+		logger.Print("⏳ %s", formatPending(dep.Pending))
+	}
+	if dep.Ignored != nil {
+		logger.Print("🙈 %s", formatIgnored(dep.Ignored))
+	}
 
 	if analysis.ShouldUpdate {
 		logger.Print("Analysis: ✅ %s", analysis.UpdateReason)
@@ -200,76 +359,146 @@ func displayDependencyInfo(logger *utils.Logger, dep *models.Dependency, analysi
 		logger.Print("Analysis: ❌ %s", analysis.RejectionReason)
 	}
 
+	displayLicense(logger, analysis.License)
+	displayGoRequirement(logger, analysis.GoRequirement)
+	displayModuleGraph(logger, analysis.ModuleGraph)
+	if analysis.SizeImpact != nil {
+		logger.Print("Binary size: %s", dependencies.FormatSizeImpact(analysis.SizeImpact))
+	}
+	displayVulnerabilities(logger, analysis.Vulnerabilities)
+	displayRisk(logger, analysis.Risk)
+	displayChangelog(logger, analysis.Changelog)
+
 	logger.Print("Recent commits:")
-	displayLimit := 5
 	for i, commit := range analysis.Commits {
-		if i >= displayLimit { // Show only first few commits
+		if i >= recentCommitLimit {
+			logger.Print("  ... and %d more", len(analysis.Commits)-recentCommitLimit)
 			break
 		}
-		logger.Print("  - %s", commit.Message)
+		logger.Print("  %d. %s", i+1, formatCommit(commit))
 	}
 }
 
-// processDependencyInteractive handles user interaction for a single dependency update
-func processDependencyInteractive(
-	updater *dependencies.DependencyUpdater,
-	logger *utils.Logger,
-	dep *models.Dependency,
-	analysis *models.UpdateAnalysis,
-	reader *bufio.Reader,
-) (bool, error) {
-	logger.Print("\nApply this update? (y/n/q): ")
-	response, _ := reader.ReadString('\n')
-	response = strings.TrimSpace(strings.ToLower(response))
-
-	switch response {
-	case "y", "yes":
-		if err := updater.ApplyUpdate(dep); err != nil {
-			logger.Error("Failed: %v", err)
-		}
-	case "q", "quit":
-		return true, nil // Signal to quit
+// displayLicense shows the license of an update, flagging a change
+func displayLicense(logger *utils.Logger, license *models.LicenseChange) {
+	switch {
+	case license == nil:
+	case license.From == license.To:
+		logger.Print("License: %s", dependencies.LicenseName(license.To))
 	default:
-		logger.Print("⏭️  Skipped")
+		logger.Print("License: ⚠️  %s → %s", dependencies.LicenseName(license.From), dependencies.LicenseName(license.To))
+	}
+}
+
+// displayGoRequirement shows the Go version the update requires, flagging a raise
+func displayGoRequirement(logger *utils.Logger, requirement *models.GoRequirement) {
+	if requirement == nil || requirement.To == "" {
+		return
 	}
 
-	return false, nil
+	goLine := "Go: " + requirement.To
+	if requirement.From != requirement.To {
+		goLine = fmt.Sprintf("Go: ⚠️  %s → %s", requirement.From, requirement.To)
+	}
+	if requirement.ToToolchain != "" {
+		goLine += " (toolchain " + requirement.ToToolchain + ")"
+	}
+	logger.Print("%s", goLine)
 }
 
-func runInteractiveMode(updater *dependencies.DependencyUpdater, logger *utils.Logger) error {
-	deps, err := updater.GetAllDependencies()
-	if err != nil {
-		return err
+// displayModuleGraph shows the modules the update adds, removes, upgrades or downgrades
+func displayModuleGraph(logger *utils.Logger, diff *models.ModuleGraphDiff) {
+	if diff == nil {
+		return
 	}
 
-	reader := bufio.NewReader(os.Stdin)
+	changes := len(diff.Added) + len(diff.Removed) + len(diff.Upgraded) + len(diff.Downgraded)
+	if changes == 0 {
+		return
+	}
 
-	for _, dep := range deps {
-		analysis, err := updater.AnalyzeDependency(dep)
-		if err != nil {
-			logger.Warn("Could not analyze %s: %v", dep.Name, err)
-			continue
-		}
+	logger.Print("Module graph changes:")
+	for _, change := range diff.Added {
+		logger.Print("  + %s %s", change.Path, change.To)
+	}
+	for _, change := range diff.Removed {
+		logger.Print("  - %s %s", change.Path, change.From)
+	}
+	for _, change := range diff.Upgraded {
+		logger.Print("  ↑ %s %s → %s", change.Path, change.From, change.To)
+	}
+	for _, change := range diff.Downgraded {
+		logger.Print("  ↓ %s %s → %s", change.Path, change.From, change.To)
+	}
+}
 
-		if !dep.UpdateNeeded {
-			continue
+// displayVulnerabilities shows the advisories affecting the current version
+func displayVulnerabilities(logger *utils.Logger, vulns []models.Vulnerability) {
+	if len(vulns) == 0 {
+		return
+	}
+
+	logger.Print("Vulnerabilities:")
+	for _, vuln := range vulns {
+		status := "not fixed by this update"
+		if vuln.FixedByUpdate {
+			status = "fixed by this update"
+		}
+		if vuln.Reachability != models.ReachabilityUnknown {
+			status += ", " + string(vuln.Reachability)
 		}
+		logger.Print("  🛡️  %s %s (%s)", vuln.ID, vuln.Summary, status)
+	}
+}
 
-		displayDependencyInfo(logger, dep, analysis)
+// displayRisk shows the risk score of an update with its contributing factors
+func displayRisk(logger *utils.Logger, risk *models.RiskAssessment) {
+	if risk == nil {
+		return
+	}
 
-		quit, err := processDependencyInteractive(updater, logger, dep, analysis, reader)
-		if err != nil {
-			return err
-		}
-		if quit {
-			return nil
+	logger.Print("Risk: %d (%s)", risk.Score, risk.Level)
+	for _, factor := range risk.Factors {
+		logger.Print("  %+3d %-18s %s", factor.Points, factor.Name, factor.Detail)
+	}
+}
+
+// displayChangelog shows the changelog entries between versions, grouped by kind
+func displayChangelog(logger *utils.Logger, changelog *models.Changelog) {
+	if changelog == nil || len(changelog.Sections) == 0 {
+		return
+	}
+
+	logger.Print("Changelog (%s):", changelog.Source)
+	for _, section := range changelog.Sections {
+		logger.Print("  %s %s", section.Version, section.Date)
+		for _, group := range models.ChangelogGroups {
+			for _, entry := range section.Entries[group] {
+				logger.Print("    %-10s %s", group, entry)
+			}
 		}
 	}
+}
 
-	// Run go mod tidy at the end of the session
-	logger.Print("\n🧹 Running go mod tidy...")
-	if err := updater.RunModTidy(); err != nil {
-		logger.Warn("go mod tidy failed: %v", err)
+// formatCommit returns the commit subject prefixed with its classification
+func formatCommit(commit models.CommitInfo) string {
+	subject, _, _ := strings.Cut(commit.Message, "\n")
+	if commit.Category == "" {
+		return subject
+	}
+	return fmt.Sprintf("[%s] %s", commit.Category, subject)
+}
+
+// runUndo reverts the last applied update session
+func runUndo(updater *dependencies.DependencyUpdater, logger *utils.Logger) error {
+	journal, err := updater.Undo()
+	if err != nil {
+		return err
+	}
+
+	logger.Print("↩️  Reverted session from %s:", journal.CreatedAt.Format(time.RFC1123))
+	for _, entry := range journal.Updates {
+		logger.Print("  %s %s → %s", entry.Module, entry.To, entry.From)
 	}
 
 	return nil
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"time"
+
+	"github.com/moeryomenko/gupdeps/internal/dependencies"
+	"github.com/moeryomenko/gupdeps/internal/utils"
+)
+
+// runPlan analyzes the dependencies and saves the approved updates as a plan
+// without modifying the project
+func runPlan(updater *dependencies.DependencyUpdater, logger *utils.Logger, out string) error {
+	deps, err := fetchAndDisplayDependencies(updater, logger)
+	if err != nil {
+		return err
+	}
+
+	results, err := analyzeDependencies(updater, logger, deps)
+	if err != nil {
+		return err
+	}
+
+	plan, err := updater.NewPlan(results.approved)
+	if err != nil {
+		return err
+	}
+	if err := dependencies.WritePlan(out, plan); err != nil {
+		return err
+	}
+
+	logger.Print("\n📝 Plan with %d updates written to %s:", len(plan.Changes), out)
+	for _, change := range plan.Changes {
+		logger.Print("  %s %s → %s", change.Module, change.From, change.To)
+	}
+	displayRejectedUpdates(logger, results.rejected)
+
+	logger.Print("\nReview the plan, then apply it with: gupdeps apply %s", out)
+	return nil
+}
+
+// runApply applies exactly the updates of a saved plan
+func runApply(updater *dependencies.DependencyUpdater, logger *utils.Logger, opts *options, filename string) error {
+	plan, err := dependencies.ReadPlan(filename)
+	if err != nil {
+		return err
+	}
+	if err := updater.CheckPlan(plan); err != nil {
+		return err
+	}
+
+	updates := plan.Updates()
+	if len(updates) == 0 {
+		logger.Print("📝 The plan has no updates to apply")
+		return nil
+	}
+
+	logger.Print("📝 Applying plan from %s made on %s", filename, plan.CreatedAt.Format(time.RFC1123))
+	if opts.batch {
+		return applyBatch(updater, logger, updates, opts.verifyCommands)
+	}
+	return applyUpdates(updater, logger, updates)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/moeryomenko/gupdeps/internal/dependencies"
+	"github.com/moeryomenko/gupdeps/internal/tui"
+	"github.com/moeryomenko/gupdeps/internal/utils"
+)
+
+// Size of the screen of scripted runs
+const (
+	scriptWidth  = 100
+	scriptHeight = 30
+)
+
+// runTUI shows the dependencies in the terminal UI, or replays a script of
+// keys on a headless screen and prints its last frame
+func runTUI(updater *dependencies.DependencyUpdater, logger *utils.Logger, opts *options) error {
+	deps, err := updater.GetAllDependencies()
+	if err != nil {
+		return err
+	}
+	if len(deps) == 0 {
+		logger.Print("No dependencies found")
+		return nil
+	}
+
+	// Log lines would break the screen, the UI reports in its status bar
+	logger.SetOutput(io.Discard)
+	app := tui.New(updater, opts.project, deps)
+
+	if opts.script != "" {
+		events, err := tui.NewScriptedEvents(opts.script)
+		if err != nil {
+			return err
+		}
+
+		screen := tui.NewHeadless(scriptWidth, scriptHeight)
+		if err := app.Run(events, screen); err != nil {
+			return err
+		}
+		fmt.Print(screen.String())
+		return nil
+	}
+
+	terminal, err := tui.OpenTerminal()
+	if err != nil {
+		return err
+	}
+	defer terminal.Close()
+
+	return app.Run(terminal.Events(), terminal)
+}
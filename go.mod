@@ -1,3 +1,15 @@
 module github.com/moeryomenko/gupdeps
 
 go 1.24.4
+
+require (
+	golang.org/x/mod v0.25.0
+	golang.org/x/term v0.32.0
+	golang.org/x/tools v0.34.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	golang.org/x/sync v0.15.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+)
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Conditions the check subcommand reports, from the least to the most severe
+const (
+	ConditionUpdates    = "updates"    // safe updates are available
+	ConditionRisky      = "risky"      // updates were rejected as risky
+	ConditionRetracted  = "retracted"  // a required version is retracted
+	ConditionVulnerable = "vulnerable" // a required version has a known vulnerability
+	ConditionErrors     = "errors"     // a dependency could not be analyzed
+)
+
+// CheckConditions lists the check conditions in order of severity
+var CheckConditions = []string{ConditionUpdates, ConditionRisky, ConditionRetracted, ConditionVulnerable, ConditionErrors}
+
+// CheckConfig controls which conditions fail the check subcommand
+type CheckConfig struct {
+	FailOn []string `yaml:"fail_on"`
+}
+
+// DefaultCheck returns the built-in check settings, failing on every condition
+func DefaultCheck() CheckConfig {
+	return CheckConfig{FailOn: slices.Clone(CheckConditions)}
+}
+
+// ParseFailOn parses a comma-separated list of check conditions
+func ParseFailOn(list string) (CheckConfig, error) {
+	check := CheckConfig{FailOn: []string{}}
+	for _, condition := range strings.Split(list, ",") {
+		if condition = strings.TrimSpace(condition); condition != "" {
+			check.FailOn = append(check.FailOn, condition)
+		}
+	}
+
+	if err := check.Validate(); err != nil {
+		return CheckConfig{}, err
+	}
+
+	return check, nil
+}
+
+// Validate checks that only known conditions are listed
+func (c *CheckConfig) Validate() error {
+	for _, condition := range c.FailOn {
+		if !slices.Contains(CheckConditions, condition) {
+			return fmt.Errorf("unknown check condition %q, expected one of %s", condition, strings.Join(CheckConditions, ", "))
+		}
+	}
+	return nil
+}
+
+// FailsOn reports whether a condition fails the check
+func (c *CheckConfig) FailsOn(condition string) bool {
+	return slices.Contains(c.FailOn, condition)
+}
@@ -0,0 +1,290 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// FileNames lists the names of the project configuration file, in order of preference
+var FileNames = []string{".gupdeps.yaml", ".gupdeps.yml"}
+
+// Formats lists the supported output formats
+var Formats = []string{"text", "json", "markdown", "sarif", "junit"}
+
+// Config holds the settings that control how dependencies are analyzed
+type Config struct {
+	Rules       *Rules           `yaml:"rules"`
+	Risk        RiskConfig       `yaml:"risk"`
+	Licenses    LicenseConfig    `yaml:"licenses"`
+	Go          GoConfig         `yaml:"go"`
+	Size        SizeConfig       `yaml:"size"`
+	Cooldown    CooldownConfig   `yaml:"cooldown"`
+	Ignore      IgnoreConfig     `yaml:",inline"`
+	Check       CheckConfig      `yaml:"check"`
+	VulnDB      string           `yaml:"vulndb,omitempty"`    // directory of a local Go vulnerability database, empty to disable
+	Include     []string         `yaml:"include,omitempty"`   // module globs or /regexps/ to analyze, all when empty
+	Exclude     []string         `yaml:"exclude,omitempty"`   // module globs or /regexps/ to skip
+	Only        string           `yaml:"-"`                   // single module to analyze, overriding include and exclude
+	Verify      []string         `yaml:"verify"`              // shell commands verifying the project after a batch
+	Format      string           `yaml:"format"`              // output format
+	CacheDir    string           `yaml:"cache_dir,omitempty"` // where repository clones are kept, temporary when empty
+	Concurrency int              `yaml:"concurrency"`         // dependencies analyzed in parallel
+	Modules     []ModuleOverride `yaml:"modules,omitempty"`
+}
+
+// ModuleOverride changes the policy for the modules matching a pattern
+type ModuleOverride struct {
+	Module        string           `yaml:"module"` // module pattern, see matchesModule
+	MaxRisk       models.RiskLevel `yaml:"max_risk,omitempty"`
+	MaxGo         string           `yaml:"max_go,omitempty"`
+	AllowLicenses []string         `yaml:"allow_licenses,omitempty"`
+}
+
+// Default returns the built-in configuration
+func Default() *Config {
+	return &Config{
+		Rules:       DefaultRules(),
+		Risk:        DefaultRisk(),
+		Licenses:    DefaultLicenses(),
+		Size:        DefaultSize(),
+		Check:       DefaultCheck(),
+		Verify:      []string{"go build ./...", "go test ./..."},
+		Format:      "text",
+		Concurrency: 1,
+	}
+}
+
+// Discover returns the configuration file in the project root, or an empty string
+func Discover(projectPath string) string {
+	for _, name := range FileNames {
+		file := filepath.Join(projectPath, name)
+		if _, err := os.Stat(file); err == nil {
+			return file
+		}
+	}
+	return ""
+}
+
+// Load reads a configuration file on top of the built-in defaults. Relative
+// paths in the file are resolved against its directory.
+func Load(filename string) (*Config, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration: %w", err)
+	}
+
+	cfg := Default()
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse configuration %s: %w", filename, err)
+	}
+
+	dir := filepath.Dir(filename)
+	cfg.VulnDB = resolvePath(dir, cfg.VulnDB)
+	cfg.CacheDir = resolvePath(dir, cfg.CacheDir)
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration %s: %w", filename, err)
+	}
+
+	return cfg, nil
+}
+
+// Validate checks every section of the configuration
+func (c *Config) Validate() error {
+	if c.Rules == nil {
+		return errors.New("rules: no categories defined")
+	}
+
+	for _, section := range []struct {
+		name     string
+		validate func() error
+	}{
+		{"rules", c.Rules.Validate},
+		{"risk", c.Risk.Validate},
+		{"licenses", c.Licenses.Validate},
+		{"go", c.Go.Validate},
+		{"size", c.Size.Validate},
+		{"cooldown", c.Cooldown.Validate},
+		{"ignore", c.Ignore.Validate},
+		{"check", c.Check.Validate},
+		{"modules", c.validateModules},
+		{"settings", c.validateSettings},
+	} {
+		if err := section.validate(); err != nil {
+			return fmt.Errorf("%s: %w", section.name, err)
+		}
+	}
+
+	return nil
+}
+
+// validateSettings checks the module patterns, the format and the concurrency
+func (c *Config) validateSettings() error {
+	for _, pattern := range slices.Concat(c.Include, c.Exclude) {
+		if err := validateModulePattern(pattern); err != nil {
+			return err
+		}
+	}
+
+	if !slices.Contains(Formats, c.Format) {
+		return fmt.Errorf("unknown format %q", c.Format)
+	}
+
+	if c.Concurrency < 1 {
+		return fmt.Errorf("concurrency must be at least 1: %d", c.Concurrency)
+	}
+
+	return nil
+}
+
+// validateModules checks the per-module overrides
+func (c *Config) validateModules() error {
+	for i := range c.Modules {
+		override := &c.Modules[i]
+		if err := validateModulePattern(override.Module); err != nil {
+			return fmt.Errorf("override %d: %w", i+1, err)
+		}
+
+		if override.MaxRisk != "" && !slices.Contains(RiskLevels, override.MaxRisk) {
+			return fmt.Errorf("%s: unknown risk level %q", override.Module, override.MaxRisk)
+		}
+
+		goConfig := GoConfig{MaxVersion: override.MaxGo}
+		if err := goConfig.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", override.Module, err)
+		}
+		override.MaxGo = goConfig.MaxVersion
+
+		if len(override.AllowLicenses) > 0 {
+			licenses := LicenseConfig{Allowed: override.AllowLicenses}
+			if err := licenses.Validate(); err != nil {
+				return fmt.Errorf("%s: %w", override.Module, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Selects reports whether a module is the only one asked for or passes the
+// include and exclude patterns
+func (c *Config) Selects(module string) bool {
+	if c.Only != "" {
+		return module == c.Only
+	}
+	if len(c.Include) > 0 && !matchesAny(c.Include, module) {
+		return false
+	}
+	return !matchesAny(c.Exclude, module)
+}
+
+// ForModule returns the configuration with the overrides matching a module
+// applied, in order
+func (c *Config) ForModule(module string) *Config {
+	effective := *c
+	for i := range c.Modules {
+		override := &c.Modules[i]
+		if !override.Matches(module) {
+			continue
+		}
+
+		if override.MaxRisk != "" {
+			effective.Risk.MaxLevel = override.MaxRisk
+		}
+		if override.MaxGo != "" {
+			effective.Go.MaxVersion = override.MaxGo
+		}
+		if len(override.AllowLicenses) > 0 {
+			effective.Licenses.Allowed = override.AllowLicenses
+		}
+	}
+	return &effective
+}
+
+// Matches reports whether the override applies to a module
+func (o *ModuleOverride) Matches(module string) bool {
+	return matchesModule(o.Module, module)
+}
+
+// matchesAny reports whether a module matches any of the patterns
+func matchesAny(patterns []string, module string) bool {
+	for _, pattern := range patterns {
+		if matchesModule(pattern, module) {
+			return true
+		}
+	}
+	return false
+}
+
+// regexpPattern returns the regular expression of a pattern written as
+// /regexp/, anchored to match whole module paths
+func regexpPattern(pattern string) (string, bool) {
+	if len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		return "^(?:" + pattern[1:len(pattern)-1] + ")$", true
+	}
+	return "", false
+}
+
+// matchesModule reports whether a module matches a glob or a /regexp/
+// pattern. A glob ending in /* also matches the modules nested at any depth
+// below the paths it matches, such as major versions and submodules. Every
+// module pattern of the configuration is matched this way.
+func matchesModule(pattern, module string) bool {
+	if expr, ok := regexpPattern(pattern); ok {
+		matched, _ := regexp.MatchString(expr, module)
+		return matched
+	}
+	if matched, _ := path.Match(pattern, module); matched {
+		return true
+	}
+
+	if !strings.HasSuffix(pattern, "/*") {
+		return false
+	}
+	for i := range len(module) {
+		if module[i] != '/' {
+			continue
+		}
+		if matched, _ := path.Match(pattern, module[:i]); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// validateModulePattern checks that a pattern is a valid glob or /regexp/
+func validateModulePattern(pattern string) error {
+	if expr, ok := regexpPattern(pattern); ok {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid module pattern %q: %w", pattern, err)
+		}
+		return nil
+	}
+
+	if _, err := path.Match(pattern, ""); pattern == "" || err != nil {
+		return fmt.Errorf("invalid module pattern %q", pattern)
+	}
+	return nil
+}
+
+// resolvePath makes a path relative to the configuration file absolute
+func resolvePath(dir, p string) string {
+	if p == "" || filepath.IsAbs(p) || strings.Contains(p, "://") {
+		return p
+	}
+	return filepath.Join(dir, p)
+}
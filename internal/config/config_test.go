@@ -0,0 +1,62 @@
+package config
+
+import "testing"
+
+func TestMatchesModule(t *testing.T) {
+	tests := []struct {
+		pattern string
+		module  string
+		want    bool
+	}{
+		{pattern: "github.com/ourorg/*", module: "github.com/ourorg/foo", want: true},
+		{pattern: "github.com/ourorg/*", module: "github.com/ourorg/foo/v2", want: true},
+		{pattern: "github.com/ourorg/*", module: "github.com/ourorg/repo/sub/pkg", want: true},
+		{pattern: "github.com/ourorg/*", module: "github.com/ourorg", want: false},
+		{pattern: "github.com/ourorg/*", module: "github.com/ourorgx/foo", want: false},
+		{pattern: "k8s.io/*", module: "sigs.k8s.io/yaml", want: false},
+		{pattern: "github.com/*/uuid", module: "github.com/google/uuid", want: true},
+		{pattern: "github.com/*/uuid", module: "github.com/google/uuid/v2", want: false},
+		{pattern: "github.com/google/uuid", module: "github.com/google/uuid", want: true},
+		{pattern: "github.com/google/uuid", module: "github.com/google/uuid/v2", want: false},
+		{pattern: `/k8s\.io\/.*/`, module: "k8s.io/client-go", want: true},
+		{pattern: `/k8s\.io\/.*/`, module: "sigs.k8s.io/yaml", want: false},
+		{pattern: `/k8s.io/`, module: "k8s.io/client-go", want: false},
+		{pattern: `/.*k8s\.io\/.*/`, module: "sigs.k8s.io/yaml", want: true},
+		{pattern: `/github\.com\/(foo|bar)/`, module: "github.com/bar", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := matchesModule(tt.pattern, tt.module); got != tt.want {
+			t.Errorf("matchesModule(%q, %q) = %v, want %v", tt.pattern, tt.module, got, tt.want)
+		}
+	}
+}
+
+func TestSelectsOnly(t *testing.T) {
+	cfg := &Config{Include: []string{"github.com/*"}, Exclude: []string{"github.com/google/*"}, Only: "github.com/google/uuid"}
+
+	if !cfg.Selects("github.com/google/uuid") {
+		t.Error("the only module must be selected whatever the patterns")
+	}
+	if cfg.Selects("github.com/pkg/errors") {
+		t.Error("modules other than the only one must not be selected")
+	}
+}
+
+func TestForModuleMatchesNestedModules(t *testing.T) {
+	cfg := Default()
+	cfg.Include = []string{"github.com/org/*"}
+	cfg.Modules = []ModuleOverride{{Module: "github.com/org/*", MaxRisk: "high"}}
+
+	for _, module := range []string{"github.com/org/a", "github.com/org/a/b", "github.com/org/a/v2"} {
+		if !cfg.Selects(module) {
+			t.Errorf("%s must be selected by the include pattern", module)
+		}
+		if got := cfg.ForModule(module).Risk.MaxLevel; got != "high" {
+			t.Errorf("max risk of %s = %s, want the override high", module, got)
+		}
+	}
+	if got := cfg.ForModule("github.com/other/a").Risk.MaxLevel; got != Default().Risk.MaxLevel {
+		t.Errorf("max risk of an unmatched module = %s, want the default", got)
+	}
+}
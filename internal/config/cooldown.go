@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CooldownConfig holds the minimum age of a release before it is proposed
+type CooldownConfig struct {
+	Days      int                `yaml:"days"` // 0 proposes releases immediately
+	Overrides []CooldownOverride `yaml:"overrides"`
+}
+
+// CooldownOverride sets the minimum release age of the modules matching a pattern
+type CooldownOverride struct {
+	Module string `yaml:"module"` // module pattern, see matchesModule
+	Days   int    `yaml:"days"`
+}
+
+// ParseCooldownOverride parses an override written as pattern=days
+func ParseCooldownOverride(value string) (CooldownOverride, error) {
+	module, days, ok := strings.Cut(value, "=")
+	if !ok {
+		return CooldownOverride{}, fmt.Errorf("invalid release age override %q, expected module=days", value)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(days))
+	if err != nil {
+		return CooldownOverride{}, fmt.Errorf("invalid release age override %q: %w", value, err)
+	}
+
+	return CooldownOverride{Module: strings.TrimSpace(module), Days: n}, nil
+}
+
+// Validate checks that the ages are not negative and the patterns are valid
+func (c *CooldownConfig) Validate() error {
+	if c.Days < 0 {
+		return fmt.Errorf("minimum release age must not be negative: %d", c.Days)
+	}
+
+	for _, override := range c.Overrides {
+		if err := validateModulePattern(override.Module); err != nil {
+			return fmt.Errorf("release age override: %w", err)
+		}
+		if override.Days < 0 {
+			return fmt.Errorf("minimum release age of %s must not be negative: %d", override.Module, override.Days)
+		}
+	}
+
+	return nil
+}
+
+// For returns the minimum release age in days of a module; the last matching override wins
+func (c *CooldownConfig) For(module string) int {
+	days := c.Days
+	for _, override := range c.Overrides {
+		if matchesModule(override.Module, module) {
+			days = override.Days
+		}
+	}
+	return days
+}
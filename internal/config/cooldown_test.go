@@ -0,0 +1,23 @@
+package config
+
+import "testing"
+
+func TestCooldownMatchesNestedModules(t *testing.T) {
+	override, err := ParseCooldownOverride("github.com/ourorg/*=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cooldown := CooldownConfig{Days: 7, Overrides: []CooldownOverride{override}}
+	if err := cooldown.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, module := range []string{"github.com/ourorg/a", "github.com/ourorg/a/v2", "github.com/ourorg/a/sub"} {
+		if days := cooldown.For(module); days != 0 {
+			t.Errorf("minimum release age of %s = %d, want the override 0", module, days)
+		}
+	}
+	if days := cooldown.For("github.com/other/a"); days != 7 {
+		t.Errorf("minimum release age of an unmatched module = %d, want 7", days)
+	}
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"fmt"
+	"go/version"
+	"strings"
+)
+
+// GoConfig holds the Go versions dependencies may require
+type GoConfig struct {
+	MaxVersion string `yaml:"max_version"` // highest Go version required by an update, empty for no limit
+}
+
+// Validate checks that the maximum is a Go version such as 1.22 or 1.22.3
+func (g *GoConfig) Validate() error {
+	g.MaxVersion = strings.TrimPrefix(g.MaxVersion, "go")
+	if g.MaxVersion != "" && !version.IsValid("go"+g.MaxVersion) {
+		return fmt.Errorf("invalid Go version %q", g.MaxVersion)
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+)
+
+// IgnoreConfig lists the updates that must not be proposed
+type IgnoreConfig struct {
+	Entries []IgnoreEntry `yaml:"ignore,omitempty"`
+}
+
+// IgnoreEntry ignores updates of the modules matching a pattern. Without a
+// version selector every update is ignored; Versions and Range ignore the
+// matching versions, while Constraint pins the module to the matching ones.
+type IgnoreEntry struct {
+	Module     string   `yaml:"module"`               // module pattern, see matchesModule
+	Versions   []string `yaml:"versions,omitempty"`   // exact versions to skip, e.g. v2.3.0
+	Range      string   `yaml:"range,omitempty"`      // versions to skip, e.g. ">=v1.5.0, <v1.6.0"
+	Constraint string   `yaml:"constraint,omitempty"` // versions to allow, e.g. ^1.4 or ~1.4.2
+	Reason     string   `yaml:"reason,omitempty"`
+	Expires    string   `yaml:"expires,omitempty"` // YYYY-MM-DD, the entry stops applying after this day
+
+	comparators []comparator
+	expires     time.Time
+}
+
+// comparator is a single version comparison such as >=v1.2.0
+type comparator struct {
+	op      string
+	version string
+}
+
+// LoadIgnore reads and validates an ignore file
+func LoadIgnore(filename string) (IgnoreConfig, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return IgnoreConfig{}, fmt.Errorf("failed to read ignore list: %w", err)
+	}
+
+	var ignore IgnoreConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&ignore); err != nil {
+		return IgnoreConfig{}, fmt.Errorf("failed to parse ignore list %s: %w", filename, err)
+	}
+
+	if err := ignore.Validate(); err != nil {
+		return IgnoreConfig{}, fmt.Errorf("invalid ignore list %s: %w", filename, err)
+	}
+
+	return ignore, nil
+}
+
+// Validate checks every entry and prepares it for matching
+func (c *IgnoreConfig) Validate() error {
+	for i := range c.Entries {
+		if err := c.Entries[i].validate(); err != nil {
+			return fmt.Errorf("entry %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// validate checks the pattern, the version selector and the expiry of an entry
+func (e *IgnoreEntry) validate() error {
+	if err := validateModulePattern(e.Module); err != nil {
+		return err
+	}
+
+	if err := e.validateSelector(); err != nil {
+		return fmt.Errorf("%s: %w", e.Module, err)
+	}
+
+	if e.Expires == "" {
+		return nil
+	}
+
+	expires, err := time.Parse(time.DateOnly, e.Expires)
+	if err != nil {
+		return fmt.Errorf("%s: invalid expiry date %q, expected YYYY-MM-DD", e.Module, e.Expires)
+	}
+	e.expires = expires
+
+	return nil
+}
+
+// validateSelector checks that at most one version selector is set and parses it
+func (e *IgnoreEntry) validateSelector() error {
+	selectors := 0
+	for _, set := range []bool{len(e.Versions) > 0, e.Range != "", e.Constraint != ""} {
+		if set {
+			selectors++
+		}
+	}
+	if selectors > 1 {
+		return fmt.Errorf("only one of versions, range and constraint may be set")
+	}
+
+	for i, version := range e.Versions {
+		e.Versions[i] = canonicalVersion(version)
+		if !semver.IsValid(e.Versions[i]) {
+			return fmt.Errorf("invalid version %q", version)
+		}
+	}
+
+	var err error
+	switch {
+	case e.Range != "":
+		e.comparators, err = parseComparators(e.Range)
+	case e.Constraint != "":
+		e.comparators, err = parseComparators(e.Constraint)
+	}
+
+	return err
+}
+
+// Expired returns the entries whose expiry date has passed
+func (c *IgnoreConfig) Expired(now time.Time) []IgnoreEntry {
+	var expired []IgnoreEntry
+	for i := range c.Entries {
+		if c.Entries[i].expired(now) {
+			expired = append(expired, c.Entries[i])
+		}
+	}
+	return expired
+}
+
+// Match returns the active entry ignoring a version of a module, or nil
+func (c *IgnoreConfig) Match(module, version string, now time.Time) *IgnoreEntry {
+	for i := range c.Entries {
+		entry := &c.Entries[i]
+		if entry.expired(now) {
+			continue
+		}
+		if matchesModule(entry.Module, module) && entry.ignores(version) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// Describe returns the reason of an entry, or a description of it when none is given
+func (e *IgnoreEntry) Describe() string {
+	if e.Reason != "" {
+		return e.Reason
+	}
+
+	switch {
+	case len(e.Versions) > 0:
+		return "ignored versions " + strings.Join(e.Versions, ", ") + " of " + e.Module
+	case e.Range != "":
+		return "ignored range " + e.Range + " of " + e.Module
+	case e.Constraint != "":
+		return e.Module + " pinned to " + e.Constraint
+	default:
+		return "updates of " + e.Module + " ignored"
+	}
+}
+
+// expired reports whether the last day of an entry has passed
+func (e *IgnoreEntry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && now.After(e.expires.AddDate(0, 0, 1))
+}
+
+// ignores reports whether an entry matching the module ignores a version
+func (e *IgnoreEntry) ignores(version string) bool {
+	switch {
+	case len(e.Versions) > 0:
+		for _, ignored := range e.Versions {
+			if semver.Compare(ignored, version) == 0 {
+				return true
+			}
+		}
+		return false
+	case e.Range != "":
+		return satisfies(e.comparators, version)
+	case e.Constraint != "":
+		return !satisfies(e.comparators, version)
+	default:
+		return true
+	}
+}
+
+// satisfies reports whether a version passes every comparator
+func satisfies(comparators []comparator, version string) bool {
+	for _, c := range comparators {
+		cmp := semver.Compare(version, c.version)
+		var ok bool
+		switch c.op {
+		case ">=":
+			ok = cmp >= 0
+		case ">":
+			ok = cmp > 0
+		case "<=":
+			ok = cmp <= 0
+		case "<":
+			ok = cmp < 0
+		default:
+			ok = cmp == 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// parseComparators parses comma or space separated comparisons (>=, >, <=, <, =)
+// and the ^ and ~ shorthands of semver constraints
+func parseComparators(expression string) ([]comparator, error) {
+	var comparators []comparator
+
+	for _, token := range strings.Fields(strings.ReplaceAll(expression, ",", " ")) {
+		op := strings.TrimRight(token[:min(2, len(token))], "v0123456789.")
+		version := canonicalVersion(token[len(op):])
+		if !semver.IsValid(version) {
+			return nil, fmt.Errorf("invalid version %q in %q", token[len(op):], expression)
+		}
+
+		switch op {
+		case "^", "~":
+			comparators = append(comparators,
+				comparator{op: ">=", version: version},
+				comparator{op: "<", version: upperBound(op, version)})
+		case ">=", ">", "<=", "<", "=", "":
+			comparators = append(comparators, comparator{op: op, version: version})
+		default:
+			return nil, fmt.Errorf("invalid operator %q in %q", op, expression)
+		}
+	}
+
+	if len(comparators) == 0 {
+		return nil, fmt.Errorf("empty version expression")
+	}
+
+	return comparators, nil
+}
+
+// upperBound returns the exclusive upper bound of a ^ or ~ constraint: ^ allows
+// changes within the major version (the minor version for v0), ~ allows patch changes
+func upperBound(op, version string) string {
+	parts := strings.SplitN(strings.TrimPrefix(semver.Canonical(version), "v"), ".", 3)
+	major, _ := strconv.Atoi(parts[0])
+	minor, _ := strconv.Atoi(parts[1])
+
+	if op == "^" && major > 0 {
+		return fmt.Sprintf("v%d.0.0", major+1)
+	}
+	return fmt.Sprintf("v%d.%d.0", major, minor+1)
+}
+
+// canonicalVersion adds the v prefix Go versions require
+func canonicalVersion(version string) string {
+	if version != "" && !strings.HasPrefix(version, "v") {
+		return "v" + version
+	}
+	return version
+}
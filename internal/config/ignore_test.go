@@ -0,0 +1,23 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIgnoreMatchesNestedModules(t *testing.T) {
+	ignore := IgnoreConfig{Entries: []IgnoreEntry{{Module: "github.com/legacy/*", Reason: "replaced"}}}
+	if err := ignore.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	for _, module := range []string{"github.com/legacy/a", "github.com/legacy/a/v2", "github.com/legacy/a/sub"} {
+		if ignore.Match(module, "v1.1.0", now) == nil {
+			t.Errorf("updates of %s must be ignored", module)
+		}
+	}
+	if ignore.Match("github.com/other/a", "v1.1.0", now) != nil {
+		t.Error("updates of unmatched modules must not be ignored")
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// LicenseConfig holds the licenses dependencies may be updated to
+type LicenseConfig struct {
+	Allowed []string `yaml:"allowed"` // SPDX identifiers
+}
+
+// spdxID matches a plausible SPDX license identifier
+var spdxID = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.+-]*$`)
+
+// DefaultLicenses returns the built-in allow-list of permissive licenses
+func DefaultLicenses() LicenseConfig {
+	return LicenseConfig{
+		Allowed: []string{"0BSD", "Apache-2.0", "BSD-2-Clause", "BSD-3-Clause", "ISC", "MIT", "MPL-2.0", "Unlicense"},
+	}
+}
+
+// ParseLicenses parses a comma-separated list of SPDX identifiers
+func ParseLicenses(list string) (LicenseConfig, error) {
+	var licenses LicenseConfig
+	for _, id := range strings.Split(list, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		licenses.Allowed = append(licenses.Allowed, id)
+	}
+
+	if err := licenses.Validate(); err != nil {
+		return LicenseConfig{}, err
+	}
+
+	return licenses, nil
+}
+
+// Validate checks that the allow-list holds SPDX identifiers
+func (l *LicenseConfig) Validate() error {
+	if len(l.Allowed) == 0 {
+		return fmt.Errorf("license allow-list is empty")
+	}
+
+	for _, id := range l.Allowed {
+		if !spdxID.MatchString(id) {
+			return fmt.Errorf("invalid SPDX license identifier %q", id)
+		}
+	}
+
+	return nil
+}
+
+// Allows reports whether a license expression is acceptable. A disjunction
+// ("MIT OR GPL-2.0") is allowed when any alternative is, a conjunction only
+// when every part is; unknown licenses are never allowed.
+func (l *LicenseConfig) Allows(expression string) bool {
+	if expression == "" {
+		return false
+	}
+
+	for _, alternative := range strings.Split(expression, " OR ") {
+		if l.allowsAll(strings.Split(alternative, " AND ")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allowsAll reports whether every license identifier is allowed
+func (l *LicenseConfig) allowsAll(ids []string) bool {
+	for _, id := range ids {
+		id = strings.Trim(id, "() ")
+		if !slices.ContainsFunc(l.Allowed, func(allowed string) bool { return strings.EqualFold(allowed, id) }) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// RiskLevels lists the risk levels in increasing order of severity
+var RiskLevels = []models.RiskLevel{
+	models.RiskLow,
+	models.RiskMedium,
+	models.RiskHigh,
+	models.RiskCritical,
+}
+
+// RiskConfig holds the thresholds that turn a risk score into a decision
+type RiskConfig struct {
+	Medium      int              `yaml:"medium"`   // minimum score of a medium risk
+	High        int              `yaml:"high"`     // minimum score of a high risk
+	Critical    int              `yaml:"critical"` // minimum score of a critical risk
+	MaxLevel    models.RiskLevel `yaml:"max_level"`
+	GraphGrowth int              `yaml:"graph_growth"` // modules an update may add before the growth counts as risk
+}
+
+// DefaultRisk returns the built-in risk thresholds
+func DefaultRisk() RiskConfig {
+	return RiskConfig{
+		Medium:      20,
+		High:        40,
+		Critical:    70,
+		MaxLevel:    models.RiskMedium,
+		GraphGrowth: 5,
+	}
+}
+
+// Validate checks that the thresholds are increasing and the level is known
+func (r *RiskConfig) Validate() error {
+	if r.Medium <= 0 || r.High <= r.Medium || r.Critical <= r.High {
+		return fmt.Errorf("risk thresholds must be positive and increasing: %d, %d, %d",
+			r.Medium, r.High, r.Critical)
+	}
+
+	if r.GraphGrowth < 0 {
+		return fmt.Errorf("module graph growth threshold must not be negative: %d", r.GraphGrowth)
+	}
+
+	if !slices.Contains(RiskLevels, r.MaxLevel) {
+		return fmt.Errorf("unknown risk level %q", r.MaxLevel)
+	}
+
+	return nil
+}
+
+// Level returns the risk level of a score
+func (r *RiskConfig) Level(score int) models.RiskLevel {
+	switch {
+	case score >= r.Critical:
+		return models.RiskCritical
+	case score >= r.High:
+		return models.RiskHigh
+	case score >= r.Medium:
+		return models.RiskMedium
+	default:
+		return models.RiskLow
+	}
+}
+
+// Approves reports whether a risk level is low enough to approve an update automatically
+func (r *RiskConfig) Approves(level models.RiskLevel) bool {
+	return slices.Index(RiskLevels, level) <= slices.Index(RiskLevels, r.MaxLevel)
+}
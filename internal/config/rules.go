@@ -0,0 +1,284 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// Decisions a category can contribute to an update
+const (
+	DecisionApprove = "approve"
+	DecisionReject  = "reject"
+	DecisionNeutral = "neutral"
+)
+
+// Category is a named commit classification rule
+type Category struct {
+	Name      string   `yaml:"name"`
+	Label     string   `yaml:"label,omitempty"`     // plural noun used in reasons, defaults to the name
+	Types     []string `yaml:"types,omitempty"`     // Conventional Commits types
+	Breaking  bool     `yaml:"breaking,omitempty"`  // matches commits with a breaking marker
+	Patterns  []string `yaml:"patterns,omitempty"`  // regular expressions matched against the message
+	Changelog []string `yaml:"changelog,omitempty"` // Keep a Changelog groups, e.g. Fixed
+	Weight    int      `yaml:"weight"`              // score contributed by every matching commit
+	Decision  string   `yaml:"decision"`            // approve, reject or neutral
+	Disabled  bool     `yaml:"disabled,omitempty"`  // removes the category in an override
+
+	compiled []*regexp.Regexp
+}
+
+// Override replaces or adds categories for modules matching a pattern
+type Override struct {
+	Module     string     `yaml:"module"` // module pattern, see matchesModule
+	Categories []Category `yaml:"categories"`
+}
+
+// Rules holds the commit classification rules
+type Rules struct {
+	Categories []Category `yaml:"categories"`
+	Overrides  []Override `yaml:"overrides,omitempty"`
+}
+
+// DefaultRules returns the built-in classification rules
+func DefaultRules() *Rules {
+	rules := &Rules{
+		Categories: []Category{
+			{
+				Name:      "break",
+				Label:     "breaking changes",
+				Breaking:  true,
+				Patterns:  []string{`(?i)\b(break(s|ing)?|remove[sd]?|deprecate[sd]?)\b`},
+				Changelog: []string{"Removed"},
+				Weight:    1,
+				Decision:  DecisionReject,
+			},
+			{
+				Name:      "fix",
+				Label:     "fixes",
+				Types:     []string{"fix"},
+				Patterns:  []string{`(?i)\b(fix(es|ed)?|bug(s|fix)?|patch(es|ed)?|resolve[sd]?|correct(s|ed)?)\b`},
+				Changelog: []string{"Fixed", "Security"},
+				Weight:    2,
+				Decision:  DecisionApprove,
+			},
+			{
+				Name:     "perf",
+				Label:    "optimizations",
+				Types:    []string{"perf"},
+				Patterns: []string{`(?i)\b(perf|optimi[sz](e|es|ed|ation)|performance|speed|faster)\b`},
+				Weight:   2,
+				Decision: DecisionApprove,
+			},
+			{
+				Name:      "feature",
+				Label:     "new features",
+				Types:     []string{"feat"},
+				Patterns:  []string{`(?i)\b(feat(ure)?s?|add(s|ed)?|new)\b`},
+				Changelog: []string{"Added"},
+				Weight:    1,
+				Decision:  DecisionApprove,
+			},
+		},
+	}
+
+	if err := rules.Validate(); err != nil {
+		panic(fmt.Sprintf("invalid default rules: %v", err))
+	}
+
+	return rules
+}
+
+// LoadRules reads and validates classification rules from a YAML file
+func LoadRules(filename string) (*Rules, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules: %w", err)
+	}
+
+	var rules Rules
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&rules); err != nil {
+		return nil, fmt.Errorf("failed to parse rules %s: %w", filename, err)
+	}
+
+	if err := rules.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rules %s: %w", filename, err)
+	}
+
+	return &rules, nil
+}
+
+// Validate checks the rules and compiles their patterns
+func (r *Rules) Validate() error {
+	if len(r.Categories) == 0 {
+		return errors.New("no categories defined")
+	}
+
+	if err := validateCategories(r.Categories); err != nil {
+		return err
+	}
+
+	for i := range r.Overrides {
+		override := &r.Overrides[i]
+		if err := validateModulePattern(override.Module); err != nil {
+			return fmt.Errorf("override %d: %w", i+1, err)
+		}
+		if err := validateCategories(override.Categories); err != nil {
+			return fmt.Errorf("override %s: %w", override.Module, err)
+		}
+	}
+
+	return nil
+}
+
+// validateCategories checks a list of categories and compiles their patterns
+func validateCategories(categories []Category) error {
+	seen := make(map[string]bool, len(categories))
+
+	for i := range categories {
+		category := &categories[i]
+		if err := category.validate(); err != nil {
+			return err
+		}
+		if seen[category.Name] {
+			return fmt.Errorf("duplicate category %q", category.Name)
+		}
+		seen[category.Name] = true
+	}
+
+	return nil
+}
+
+// validate checks a single category and compiles its patterns
+func (c *Category) validate() error {
+	if c.Name == "" {
+		return errors.New("category without a name")
+	}
+
+	if c.Disabled {
+		return nil
+	}
+
+	switch c.Decision {
+	case DecisionApprove, DecisionReject, DecisionNeutral:
+	default:
+		return fmt.Errorf("category %s: unknown decision %q", c.Name, c.Decision)
+	}
+
+	if c.Weight < 0 {
+		return fmt.Errorf("category %s: negative weight", c.Name)
+	}
+
+	if len(c.Types) == 0 && len(c.Patterns) == 0 && len(c.Changelog) == 0 && !c.Breaking {
+		return fmt.Errorf("category %s: needs types, patterns, changelog or breaking", c.Name)
+	}
+
+	return c.compile()
+}
+
+// compile checks the changelog groups and compiles the category patterns
+func (c *Category) compile() error {
+	for _, group := range c.Changelog {
+		if !slices.Contains(models.ChangelogGroups, group) {
+			return fmt.Errorf("category %s: unknown changelog group %q", c.Name, group)
+		}
+	}
+
+	c.compiled = make([]*regexp.Regexp, 0, len(c.Patterns))
+	for _, pattern := range c.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("category %s: %w", c.Name, err)
+		}
+		c.compiled = append(c.compiled, re)
+	}
+
+	return nil
+}
+
+// For returns the categories that apply to a module, with overrides merged in order
+func (r *Rules) For(module string) []Category {
+	categories := slices.Clone(r.Categories)
+
+	for _, override := range r.Overrides {
+		if !matchesModule(override.Module, module) {
+			continue
+		}
+
+		for i := range override.Categories {
+			replacement := &override.Categories[i]
+			idx := slices.IndexFunc(categories, func(c Category) bool { return c.Name == replacement.Name })
+			switch {
+			case replacement.Disabled && idx >= 0:
+				categories = slices.Delete(categories, idx, idx+1)
+			case replacement.Disabled:
+			case idx >= 0:
+				categories[idx] = *replacement
+			default:
+				categories = append(categories, *replacement)
+			}
+		}
+	}
+
+	return categories
+}
+
+// ReasonLabel returns the noun used for the category in decision reasons
+func (c *Category) ReasonLabel() string {
+	if c.Label != "" {
+		return c.Label
+	}
+	return c.Name
+}
+
+// MatchesChangelog reports whether a changelog group belongs to the category
+func (c *Category) MatchesChangelog(group string) bool {
+	return slices.Contains(c.Changelog, group)
+}
+
+// housekeepingSubject matches free-form subjects of changes to documentation,
+// CI or code style, which Conventional Commits would type as docs, ci or style
+var housekeepingSubject = regexp.MustCompile(
+	`(?i)\b(typos?|spelling|docs?|documentation|godoc|readme|changelog|ci|lint(s|er|ing)?|golangci(-lint)?|gofmt|whitespace)\b`)
+
+// Matches reports whether a commit belongs to the category. Conventional
+// commits are matched by type and breaking marker; patterns are used for
+// free-form messages of non-conforming repositories, except for housekeeping
+// subjects, or for every commit when the category defines neither types nor
+// a breaking marker.
+func (c *Category) Matches(commit *models.CommitInfo, conforming bool) bool {
+	patternsOnly := len(c.Types) == 0 && !c.Breaking
+
+	if commit.Conventional && !patternsOnly {
+		return (c.Breaking && commit.Breaking) || slices.Contains(c.Types, commit.Type)
+	}
+
+	if !commit.Conventional && !patternsOnly {
+		subject, _, _ := strings.Cut(commit.Message, "\n")
+		if conforming || housekeepingSubject.MatchString(subject) {
+			return false
+		}
+	}
+
+	return c.matchesPattern(commit.Message)
+}
+
+// matchesPattern reports whether any of the category patterns matches the message
+func (c *Category) matchesPattern(message string) bool {
+	for _, re := range c.compiled {
+		if re.MatchString(message) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRulesMatchNestedModules(t *testing.T) {
+	rules := DefaultRules()
+	rules.Overrides = []Override{{
+		Module:     "github.com/ourorg/*",
+		Categories: []Category{{Name: "break", Disabled: true}},
+	}}
+	if err := rules.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	hasBreak := func(module string) bool {
+		return slices.ContainsFunc(rules.For(module), func(c Category) bool { return c.Name == "break" })
+	}
+	for _, module := range []string{"github.com/ourorg/a", "github.com/ourorg/a/v2", "github.com/ourorg/a/sub"} {
+		if hasBreak(module) {
+			t.Errorf("the override must disable the break category of %s", module)
+		}
+	}
+	if !hasBreak("github.com/other/a") {
+		t.Error("unmatched modules must keep the break category")
+	}
+}
@@ -0,0 +1,22 @@
+package config
+
+import "fmt"
+
+// SizeConfig controls the binary size impact analysis
+type SizeConfig struct {
+	Enabled   bool `yaml:"enabled"`
+	MaxGrowth int  `yaml:"max_growth"` // binary growth in percent above which an update is flagged
+}
+
+// DefaultSize returns the built-in size analysis settings
+func DefaultSize() SizeConfig {
+	return SizeConfig{MaxGrowth: 5}
+}
+
+// Validate checks that the growth threshold is not negative
+func (s *SizeConfig) Validate() error {
+	if s.MaxGrowth < 0 {
+		return fmt.Errorf("binary size growth threshold must not be negative: %d", s.MaxGrowth)
+	}
+	return nil
+}
@@ -2,95 +2,230 @@ package dependencies
 
 import (
 	"fmt"
-	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/moeryomenko/gupdeps/internal/config"
 	"github.com/moeryomenko/gupdeps/internal/models"
 	"github.com/moeryomenko/gupdeps/internal/utils"
 )
 
 // CommitAnalyzer analyzes commits to determine if updates should be applied
 type CommitAnalyzer struct {
+	rules  *config.Rules
 	logger *utils.Logger
 }
 
 // NewCommitAnalyzer creates a new commit analyzer
-func NewCommitAnalyzer(logger *utils.Logger) *CommitAnalyzer {
+func NewCommitAnalyzer(rules *config.Rules, logger *utils.Logger) *CommitAnalyzer {
 	return &CommitAnalyzer{
+		rules:  rules,
 		logger: logger,
 	}
 }
 
-// AnalyzeCommits analyzes commit messages to determine if update should be applied
-func (ca *CommitAnalyzer) AnalyzeCommits(commits []models.CommitInfo) (shouldUpdate bool, reason, riskLevel string) {
-	patterns := map[string]*regexp.Regexp{
-		"fix":     regexp.MustCompile(`(?i)(fix|bug|patch|resolve|correct)`),
-		"perf":    regexp.MustCompile(`(?i)(perf|optimize|performance|speed|faster)`),
-		"break":   regexp.MustCompile(`(?i)(breaking|break|remove|deprecate|BREAKING)`),
-		"feature": regexp.MustCompile(`(?i)(feat|feature|add|new)`),
+// conventionalThreshold is the share of Conventional Commits above which a
+// repository is considered conforming and keyword heuristics are not used
+const conventionalThreshold = 0.5
+
+// reasonNoImprovements is the rejection reason of updates without any decisive change
+const reasonNoImprovements = "No significant improvements found"
+
+// categoryScore is the number of commits and the weighted score of a category
+type categoryScore struct {
+	category *config.Category
+	count    int
+	score    int
+}
+
+// AnalyzeCommits analyzes commit messages of a module to determine if update should be applied.
+// The classification of each commit is stored in its Category field.
+func (ca *CommitAnalyzer) AnalyzeCommits(module string, commits []models.CommitInfo) (shouldUpdate bool, reason, rejection string) {
+	categories := ca.rules.For(module)
+
+	conforming := ca.classifyCommits(categories, commits)
+	ca.logger.Info("Classified %d commits of %s (conventional: %t)", len(commits), module, conforming)
+
+	return ca.decide(ca.scoreCategories(categories, countCommits(commits)))
+}
+
+// AnalyzeChangelog analyzes the changelog entries of a module to determine if
+// update should be applied. It reports false when no entry could be classified.
+func (ca *CommitAnalyzer) AnalyzeChangelog(module string, changelog *models.Changelog) (shouldUpdate bool, reason, rejection string, ok bool) {
+	counts := countChangelog(ca.rules.For(module), changelog)
+	if len(counts) == 0 {
+		return false, "", "", false
 	}
 
-	counts := map[string]int{
-		"fix":     0,
-		"perf":    0,
-		"break":   0,
-		"feature": 0,
+	shouldUpdate, reason, rejection = ca.decide(ca.scoreCategories(ca.rules.For(module), counts))
+	return shouldUpdate, reason, rejection, true
+}
+
+// decide turns category scores into a decision: any rejecting category vetoes the update
+func (ca *CommitAnalyzer) decide(scores []categoryScore) (shouldUpdate bool, reason, rejection string) {
+	if rejected := filterScores(scores, config.DecisionReject, 0); len(rejected) > 0 {
+		return false, "", ca.formatRejectionReason(rejected)
 	}
 
-	for _, commit := range commits {
-		msg := commit.Message
-		for category, pattern := range patterns {
-			if pattern.MatchString(msg) {
-				counts[category]++
+	if approved := filterScores(scores, config.DecisionApprove, 1); len(approved) > 0 {
+		return true, ca.formatApprovalReason(approved), ""
+	}
+
+	return false, "", reasonNoImprovements
+}
+
+// classifyCommits assigns a category to every commit. Conventional Commits are
+// classified by type and breaking marker; keyword heuristics are used only
+// when the repository does not follow the specification. It reports whether
+// the repository was treated as conforming.
+func (ca *CommitAnalyzer) classifyCommits(categories []config.Category, commits []models.CommitInfo) bool {
+	conventional := 0
+	for i := range commits {
+		if parseConventionalCommit(&commits[i]) {
+			conventional++
+		}
+	}
+
+	conforming := len(commits) > 0 && float64(conventional)/float64(len(commits)) >= conventionalThreshold
+
+	for i := range commits {
+		commit := &commits[i]
+		commit.Category = ""
+		for j := range categories {
+			if categories[j].Matches(commit, conforming) {
+				commit.Category = categories[j].Name
+				break
 			}
 		}
 	}
 
-	// Decision logic
-	if counts["break"] > 0 {
-		return false, "", ca.formatRejectionReason(counts["break"])
+	return conforming
+}
+
+// countCommits returns the number of classified commits per category
+func countCommits(commits []models.CommitInfo) map[string]int {
+	counts := make(map[string]int)
+	for _, commit := range commits {
+		if commit.Category != "" {
+			counts[commit.Category]++
+		}
 	}
+	return counts
+}
 
-	if counts["fix"] > 0 || counts["perf"] > 0 {
-		return true, ca.formatApprovalReason(counts), ""
+// countChangelog returns the number of changelog entries per category, each
+// group counting towards the first category that claims it
+func countChangelog(categories []config.Category, changelog *models.Changelog) map[string]int {
+	counts := make(map[string]int)
+	if changelog == nil {
+		return counts
 	}
 
-	if counts["feature"] > 0 {
-		return true, ca.formatFeatureReason(counts["feature"]), ""
+	for _, section := range changelog.Sections {
+		for group, entries := range section.Entries {
+			for i := range categories {
+				if categories[i].MatchesChangelog(group) {
+					counts[categories[i].Name] += len(entries)
+					break
+				}
+			}
+		}
 	}
 
-	return false, "", "No significant improvements found"
+	return counts
 }
 
-// formatRejectionReason creates a rejection message
-func (ca *CommitAnalyzer) formatRejectionReason(breakingChanges int) string {
-	return fmt.Sprintf("Contains %d breaking changes", breakingChanges)
+// scoreCategories weighs the number of items of every category
+func (ca *CommitAnalyzer) scoreCategories(categories []config.Category, counts map[string]int) []categoryScore {
+	scores := make([]categoryScore, len(categories))
+	for i := range categories {
+		scores[i] = categoryScore{
+			category: &categories[i],
+			count:    counts[categories[i].Name],
+			score:    counts[categories[i].Name] * categories[i].Weight,
+		}
+	}
+	return scores
 }
 
-// formatApprovalReason creates an approval message based on fixes and optimizations
-func (ca *CommitAnalyzer) formatApprovalReason(counts map[string]int) string {
-	var reasons []string
-	if counts["fix"] > 0 {
-		reasons = append(reasons, fmt.Sprintf("%d fixes", counts["fix"]))
+// filterScores returns categories with the given decision that have matching
+// commits and at least the minimum score, highest score first
+func filterScores(scores []categoryScore, decision string, minScore int) []categoryScore {
+	var filtered []categoryScore
+	for _, score := range scores {
+		if score.category.Decision == decision && score.count > 0 && score.score >= minScore {
+			filtered = append(filtered, score)
+		}
 	}
-	if counts["perf"] > 0 {
-		reasons = append(reasons, fmt.Sprintf("%d optimizations", counts["perf"]))
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].score > filtered[j].score
+	})
+
+	return filtered
+}
+
+// formatRejectionReason creates a rejection message
+func (ca *CommitAnalyzer) formatRejectionReason(rejected []categoryScore) string {
+	return "Contains " + formatCounts(rejected)
+}
+
+// formatApprovalReason creates an approval message based on the approving categories
+func (ca *CommitAnalyzer) formatApprovalReason(approved []categoryScore) string {
+	return formatCounts(approved)
+}
+
+// formatCounts lists the commit count of every category
+func formatCounts(scores []categoryScore) string {
+	reasons := make([]string, 0, len(scores))
+	for _, score := range scores {
+		reasons = append(reasons, fmt.Sprintf("%d %s", score.count, score.category.ReasonLabel()))
 	}
 	return strings.Join(reasons, ", ")
 }
 
-// formatFeatureReason creates a feature-based approval message
-func (ca *CommitAnalyzer) formatFeatureReason(featureCount int) string {
-	return fmt.Sprintf("%d new features", featureCount)
+// RejectScore returns the number and weighted score of the items in rejecting
+// categories, taken from the changelog when it drove the decision
+func (ca *CommitAnalyzer) RejectScore(analysis *models.UpdateAnalysis) (count, score int) {
+	categories := ca.rules.For(analysis.Dependency.Name)
+
+	counts := countChangelog(categories, analysis.Changelog)
+	if len(counts) == 0 {
+		counts = countCommits(analysis.Commits)
+	}
+
+	for _, s := range ca.scoreCategories(categories, counts) {
+		if s.category.Decision == config.DecisionReject {
+			count += s.count
+			score += s.score
+		}
+	}
+	return count, score
 }
 
-// AnalyzeUpdate performs complete analysis for a dependency update
-func (ca *CommitAnalyzer) AnalyzeUpdate(dep *models.Dependency, commits []models.CommitInfo) *models.UpdateAnalysis {
-	shouldUpdate, reason, rejection := ca.AnalyzeCommits(commits)
+// AnalyzeUpdate performs complete analysis for a dependency update. Curated
+// changelog entries take precedence over commit messages when available.
+func (ca *CommitAnalyzer) AnalyzeUpdate(
+	dep *models.Dependency,
+	commits []models.CommitInfo,
+	changelog *models.Changelog,
+) *models.UpdateAnalysis {
+	shouldUpdate, reason, rejection := ca.AnalyzeCommits(dep.Name, commits)
+
+	if clShouldUpdate, clReason, clRejection, ok := ca.AnalyzeChangelog(dep.Name, changelog); ok {
+		shouldUpdate, reason, rejection = clShouldUpdate, clReason, clRejection
+		if reason != "" {
+			reason += " (from changelog)"
+		}
+		if rejection != "" {
+			rejection += " (from changelog)"
+		}
+	}
 
 	return &models.UpdateAnalysis{
 		Dependency:      dep,
 		Commits:         commits,
+		Changelog:       changelog,
 		ShouldUpdate:    shouldUpdate,
 		UpdateReason:    reason,
 		RejectionReason: rejection,
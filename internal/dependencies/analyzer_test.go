@@ -0,0 +1,45 @@
+package dependencies
+
+import (
+	"io"
+	"testing"
+
+	"github.com/moeryomenko/gupdeps/internal/config"
+	"github.com/moeryomenko/gupdeps/internal/models"
+	"github.com/moeryomenko/gupdeps/internal/utils"
+)
+
+func TestClassifyHeuristicCommits(t *testing.T) {
+	logger := utils.NewLogger(false)
+	logger.SetOutput(io.Discard)
+	ca := NewCommitAnalyzer(config.DefaultRules(), logger)
+
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{message: "Fix crash on empty input", want: "fix"},
+		{message: "Fixed race in the connection pool", want: "fix"},
+		{message: "Add support for contexts", want: "feature"},
+		{message: "Remove deprecated Dial", want: "break"},
+		{message: "Fix typo in README", want: ""},
+		{message: "fix lint", want: ""},
+		{message: "Fix CI on Windows", want: ""},
+		{message: "Remove outdated docs", want: ""},
+		{message: "Fix parser crash\n\nSee the docs for the syntax", want: "fix"},
+	}
+
+	commits := make([]models.CommitInfo, len(tests))
+	for i, tt := range tests {
+		commits[i].Message = tt.message
+	}
+
+	if ca.classifyCommits(config.DefaultRules().Categories, commits) {
+		t.Fatal("free-form commits must not be treated as conforming")
+	}
+	for i, tt := range tests {
+		if got := commits[i].Category; got != tt.want {
+			t.Errorf("category of %q = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,392 @@
+package dependencies
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// diffModuleAPI compares the exported API of two extracted module versions.
+// Symbols are compared by their declared signatures without parameter names;
+// removed or changed symbols are incompatible, added symbols are compatible.
+func diffModuleAPI(oldDir, newDir string) (*models.APIDiff, error) {
+	oldAPI, err := exportedAPI(oldDir)
+	if err != nil {
+		return nil, err
+	}
+
+	newAPI, err := exportedAPI(newDir)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &models.APIDiff{}
+	for symbol, oldSig := range oldAPI {
+		newSig, ok := newAPI[symbol]
+		switch {
+		case !ok:
+			diff.Incompatible = append(diff.Incompatible, symbol+": removed")
+		case newSig != oldSig:
+			diff.Incompatible = append(diff.Incompatible, symbol+": changed from "+oldSig+" to "+newSig)
+		}
+	}
+
+	for symbol := range newAPI {
+		if _, ok := oldAPI[symbol]; !ok {
+			diff.Compatible = append(diff.Compatible, symbol+": added")
+		}
+	}
+
+	sort.Strings(diff.Incompatible)
+	sort.Strings(diff.Compatible)
+
+	return diff, nil
+}
+
+// exportedAPI returns the exported symbols of all public packages in a module
+// directory, mapped to their printed signatures. A symbol declared in several
+// files for different build constraints maps to all of its signatures.
+func exportedAPI(moduleDir string) (map[string]string, error) {
+	api := make(apiSymbols)
+	fset := token.NewFileSet()
+
+	err := filepath.WalkDir(moduleDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if path != moduleDir && skipPackageDir(path, entry.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		// Unparsable files and commands are not part of the API
+		file, parseErr := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if parseErr != nil || file.Name.Name == "main" {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(moduleDir, filepath.Dir(path))
+		prefix := ""
+		if rel != "." {
+			prefix = filepath.ToSlash(rel) + "."
+		}
+
+		normalizeSignatures(file)
+		collectFileAPI(fset, file, prefix, api)
+		return nil
+	})
+
+	return api.signatures(), err
+}
+
+// apiSymbols maps exported symbols to the signatures they are declared with
+type apiSymbols map[string][]string
+
+// add records a signature of a symbol
+func (api apiSymbols) add(symbol, signature string) {
+	if !slices.Contains(api[symbol], signature) {
+		api[symbol] = append(api[symbol], signature)
+	}
+}
+
+// signatures joins the signatures of each symbol in a stable order
+func (api apiSymbols) signatures() map[string]string {
+	joined := make(map[string]string, len(api))
+	for symbol, signatures := range api {
+		slices.Sort(signatures)
+		joined[symbol] = strings.Join(signatures, " | ")
+	}
+	return joined
+}
+
+// normalizeSignatures removes the names of the parameters and results of all
+// function types in a file and numbers the type parameters of each
+// declaration, as renaming or regrouping them does not change the API
+func normalizeSignatures(file *ast.File) {
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncDecl:
+			if node.Recv != nil {
+				renameTypeParams(funcTypeParams(node), node.Recv, node.Type)
+			} else {
+				renameTypeParams(funcTypeParams(node), node.Type)
+			}
+		case *ast.TypeSpec:
+			renameTypeParams(fieldNames(node.TypeParams), node.TypeParams, node.Type)
+		case *ast.FuncType:
+			node.Params = unnamedFields(node.Params)
+			node.Results = unnamedFields(node.Results)
+		}
+		return true
+	})
+}
+
+// funcTypeParams returns the type parameters of a function or of the receiver of a method
+func funcTypeParams(decl *ast.FuncDecl) []*ast.Ident {
+	params := fieldNames(decl.Type.TypeParams)
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return params
+	}
+
+	recv := decl.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	switch recv := recv.(type) {
+	case *ast.IndexExpr:
+		params = append(params, identList(recv.Index)...)
+	case *ast.IndexListExpr:
+		params = append(params, identList(recv.Indices...)...)
+	}
+	return params
+}
+
+// fieldNames returns the names declared by a field list
+func fieldNames(fields *ast.FieldList) []*ast.Ident {
+	if fields == nil {
+		return nil
+	}
+
+	var names []*ast.Ident
+	for _, field := range fields.List {
+		names = append(names, field.Names...)
+	}
+	return names
+}
+
+// identList returns the identifiers among expressions
+func identList(exprs ...ast.Expr) []*ast.Ident {
+	var idents []*ast.Ident
+	for _, expr := range exprs {
+		if ident, ok := expr.(*ast.Ident); ok {
+			idents = append(idents, ident)
+		}
+	}
+	return idents
+}
+
+// renameTypeParams renames type parameters and their uses within the given
+// nodes to their position, leaving selectors and the names of fields alone
+func renameTypeParams(params []*ast.Ident, nodes ...ast.Node) {
+	if len(params) == 0 {
+		return
+	}
+
+	names := make(map[string]string, len(params))
+	for i, param := range params {
+		names[param.Name] = fmt.Sprintf("T%d", i+1)
+	}
+
+	var uses []*ast.Ident
+	var collect func(node ast.Node) bool
+	collect = func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.SelectorExpr:
+			ast.Inspect(node.X, collect)
+			return false
+		case *ast.Field:
+			ast.Inspect(node.Type, collect)
+			return false
+		case *ast.Ident:
+			if _, ok := names[node.Name]; ok && !slices.Contains(params, node) {
+				uses = append(uses, node)
+			}
+		}
+		return true
+	}
+	for _, node := range nodes {
+		ast.Inspect(node, collect)
+	}
+
+	for _, ident := range append(uses, params...) {
+		ident.Name = names[ident.Name]
+	}
+}
+
+// unnamedFields returns a field list with one unnamed field per declared name
+func unnamedFields(fields *ast.FieldList) *ast.FieldList {
+	if fields == nil {
+		return nil
+	}
+
+	unnamed := &ast.FieldList{Opening: fields.Opening, Closing: fields.Closing}
+	for _, field := range fields.List {
+		for range max(1, len(field.Names)) {
+			unnamed.List = append(unnamed.List, &ast.Field{Type: field.Type})
+		}
+	}
+	return unnamed
+}
+
+// skipPackageDir reports whether a directory is not part of the public API
+func skipPackageDir(path, name string) bool {
+	if name == "testdata" || name == "vendor" || name == "internal" ||
+		strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+		return true
+	}
+
+	// Nested modules are versioned independently
+	_, err := os.Stat(filepath.Join(path, "go.mod"))
+	return err == nil
+}
+
+// collectFileAPI adds the exported declarations of a file to the API map
+func collectFileAPI(fset *token.FileSet, file *ast.File, prefix string, api apiSymbols) {
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			collectFuncAPI(fset, decl, prefix, api)
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					collectTypeAPI(fset, spec, prefix, api)
+				case *ast.ValueSpec:
+					collectValueAPI(fset, decl.Tok, spec, prefix, api)
+				}
+			}
+		}
+	}
+}
+
+// collectFuncAPI adds an exported function or method
+func collectFuncAPI(fset *token.FileSet, decl *ast.FuncDecl, prefix string, api apiSymbols) {
+	if !decl.Name.IsExported() {
+		return
+	}
+
+	name := decl.Name.Name
+	if decl.Recv != nil && len(decl.Recv.List) > 0 {
+		recv := receiverName(decl.Recv.List[0].Type)
+		if !ast.IsExported(recv) {
+			return
+		}
+		name = recv + "." + name
+	}
+
+	api.add(prefix+name, "func"+strings.TrimPrefix(printNode(fset, decl.Type), "func"))
+}
+
+// collectTypeAPI adds an exported type with its exported fields and methods
+func collectTypeAPI(fset *token.FileSet, spec *ast.TypeSpec, prefix string, api apiSymbols) {
+	if !spec.Name.IsExported() {
+		return
+	}
+
+	name := prefix + spec.Name.Name
+	params := ""
+	if spec.TypeParams != nil {
+		params = "[" + printFieldList(fset, spec.TypeParams) + "]"
+	}
+
+	switch typ := spec.Type.(type) {
+	case *ast.StructType:
+		api.add(name, "struct"+params)
+		collectFieldsAPI(fset, typ.Fields, name, api)
+	case *ast.InterfaceType:
+		api.add(name, "interface"+params)
+		collectFieldsAPI(fset, typ.Methods, name, api)
+	default:
+		alias := ""
+		if spec.Assign.IsValid() {
+			alias = "= "
+		}
+		api.add(name, params+alias+printNode(fset, spec.Type))
+	}
+}
+
+// collectFieldsAPI adds the exported fields of a struct or methods of an interface
+func collectFieldsAPI(fset *token.FileSet, fields *ast.FieldList, owner string, api apiSymbols) {
+	if fields == nil {
+		return
+	}
+
+	for _, field := range fields.List {
+		typ := printNode(fset, field.Type)
+		if len(field.Names) == 0 {
+			// Embedded field, named after its type
+			if embedded := receiverName(field.Type); ast.IsExported(embedded) {
+				api.add(owner+"."+embedded, "embedded "+typ)
+			}
+			continue
+		}
+
+		for _, ident := range field.Names {
+			if ident.IsExported() {
+				api.add(owner+"."+ident.Name, typ)
+			}
+		}
+	}
+}
+
+// collectValueAPI adds exported constants and variables
+func collectValueAPI(fset *token.FileSet, tok token.Token, spec *ast.ValueSpec, prefix string, api apiSymbols) {
+	typ := ""
+	if spec.Type != nil {
+		typ = " " + printNode(fset, spec.Type)
+	}
+
+	for _, ident := range spec.Names {
+		if ident.IsExported() {
+			api.add(prefix+ident.Name, tok.String()+typ)
+		}
+	}
+}
+
+// receiverName returns the base type name of a receiver or embedded field expression
+func receiverName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(expr.X)
+	case *ast.IndexExpr:
+		return receiverName(expr.X)
+	case *ast.IndexListExpr:
+		return receiverName(expr.X)
+	case *ast.SelectorExpr:
+		return expr.Sel.Name
+	case *ast.Ident:
+		return expr.Name
+	default:
+		return ""
+	}
+}
+
+// printFieldList prints a type parameter list without brackets
+func printFieldList(fset *token.FileSet, fields *ast.FieldList) string {
+	parts := make([]string, 0, len(fields.List))
+	for _, field := range fields.List {
+		names := make([]string, 0, len(field.Names))
+		for _, ident := range field.Names {
+			names = append(names, ident.Name)
+		}
+		parts = append(parts, strings.Join(names, ", ")+" "+printNode(fset, field.Type))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// printNode prints an AST node as Go source
+func printNode(fset *token.FileSet, node ast.Node) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
@@ -0,0 +1,78 @@
+package dependencies
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiffModuleAPI(t *testing.T) {
+	tests := []struct {
+		name         string
+		old, new     map[string]string
+		incompatible []string
+		compatible   []string
+	}{
+		{
+			name:         "parameter renamed",
+			old:          map[string]string{"a.go": "package a\n\nfunc F(a int) error { return nil }\n"},
+			new:          map[string]string{"a.go": "package a\n\nfunc F(b int) (err error) { return nil }\n"},
+			incompatible: nil,
+		},
+		{
+			name:         "parameters regrouped",
+			old:          map[string]string{"a.go": "package a\n\nfunc F(a, b int, s ...string) {}\n"},
+			new:          map[string]string{"a.go": "package a\n\nfunc F(a int, b int, rest ...string) {}\n"},
+			incompatible: nil,
+		},
+		{
+			name: "type parameter renamed",
+			old: map[string]string{"a.go": "package a\n\ntype List[T any] struct{ Items []T }\n\n" +
+				"func (l *List[T]) Push(v T) {}\n\nfunc Map[K comparable, V any](m map[K]V) {}\n"},
+			new: map[string]string{"a.go": "package a\n\ntype List[E any] struct{ Items []E }\n\n" +
+				"func (l *List[X]) Push(item X) {}\n\nfunc Map[Key comparable, Value any](values map[Key]Value) {}\n"},
+			incompatible: nil,
+		},
+		{
+			name: "declared per build constraint",
+			old: map[string]string{
+				"a_unix.go":    "//go:build unix\n\npackage a\n\ntype Handle int\n",
+				"a_windows.go": "//go:build windows\n\npackage a\n\ntype Handle uintptr\n",
+			},
+			new: map[string]string{
+				"a_other.go":   "//go:build !windows\n\npackage a\n\ntype Handle int\n",
+				"a_windows.go": "//go:build windows\n\npackage a\n\ntype Handle uintptr\n",
+			},
+			incompatible: nil,
+		},
+		{
+			name:         "parameter type changed",
+			old:          map[string]string{"a.go": "package a\n\nfunc F(a int) {}\n"},
+			new:          map[string]string{"a.go": "package a\n\nfunc F(a int64) {}\n\nfunc G() {}\n"},
+			incompatible: []string{"F: changed from func(int) to func(int64)"},
+			compatible:   []string{"G: added"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldDir, newDir := t.TempDir(), t.TempDir()
+			for name, content := range tt.old {
+				writeTestFile(t, oldDir, name, content)
+			}
+			for name, content := range tt.new {
+				writeTestFile(t, newDir, name, content)
+			}
+
+			diff, err := diffModuleAPI(oldDir, newDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(diff.Incompatible, tt.incompatible) {
+				t.Errorf("incompatible = %q, want %q", diff.Incompatible, tt.incompatible)
+			}
+			if !slices.Equal(diff.Compatible, tt.compatible) {
+				t.Errorf("compatible = %q, want %q", diff.Compatible, tt.compatible)
+			}
+		})
+	}
+}
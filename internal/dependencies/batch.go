@@ -0,0 +1,196 @@
+package dependencies
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+	"github.com/moeryomenko/gupdeps/internal/utils"
+)
+
+// BatchResult describes the outcome of applying a batch of updates
+type BatchResult struct {
+	Applied  []*models.UpdateAnalysis
+	Culprits []*models.UpdateAnalysis
+}
+
+// Verify runs the verification commands in the project through sh -c, so
+// they are quoted as in a shell, returning the captured output of the first
+// failing command
+func (du *DependencyUpdater) Verify(commands []string) (string, error) {
+	for _, command := range commands {
+		if strings.TrimSpace(command) == "" {
+			continue
+		}
+
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Dir = du.projectPath
+
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return string(output), fmt.Errorf("%s failed: %w", command, err)
+		}
+	}
+
+	return "", nil
+}
+
+// ApplyBatch applies all updates together and verifies the result. If the
+// verification fails, the batch is bisected to isolate the offending
+// updates and the largest passing subset is applied instead.
+func (s *Session) ApplyBatch(analyses []*models.UpdateAnalysis, commands []string) (*BatchResult, error) {
+	result := &BatchResult{}
+	if len(analyses) == 0 {
+		return result, nil
+	}
+
+	output, err := s.trial(analyses, commands)
+	if err == nil {
+		return s.finishBatch(analyses, result)
+	}
+
+	// Bisecting assumes the project passes verification without any updates
+	if baseOutput, baseErr := s.trial(nil, commands); baseErr != nil {
+		return nil, s.fail(fmt.Errorf("project fails verification before updates: %w\nOutput: %s", baseErr, baseOutput))
+	}
+
+	trial := func(subset []*models.UpdateAnalysis) (string, error) {
+		return s.trial(subset, commands)
+	}
+	good, culprits := bisect(analyses, output, trial, s.updater.logger)
+	result.Culprits = culprits
+
+	return s.finishBatch(good, result)
+}
+
+// trialFunc applies a subset of the updates and verifies it, returning the
+// output of a failing verification
+type trialFunc func(analyses []*models.UpdateAnalysis) (string, error)
+
+// bisect isolates the updates that fail verification in a batch whose trial
+// failed with the given output. Culprits are found one at a time, from the
+// first one in the batch, and the updates after each culprit are tried again
+// without it, so only the updates that fail on their own are dropped.
+func bisect(analyses []*models.UpdateAnalysis, output string, trial trialFunc, logger *utils.Logger) (good, culprits []*models.UpdateAnalysis) {
+	remaining := analyses
+
+	for len(remaining) > 0 {
+		// good+remaining[:lo] is known to pass, good+remaining[:hi] is known to fail
+		lo, hi := 0, len(remaining)
+		for hi-lo > 1 {
+			mid := (lo + hi) / 2
+			logger.Print("🪓 Bisecting: trying %d of %d remaining updates...", mid, len(remaining))
+
+			midOutput, midErr := trial(append(good[:len(good):len(good)], remaining[:mid]...))
+			if midErr == nil {
+				lo = mid
+			} else {
+				hi, output = mid, midOutput
+			}
+		}
+
+		culprit := remaining[hi-1]
+		culprit.Outcome = &models.UpdateOutcome{Output: output}
+		culprits = append(culprits, culprit)
+		logger.Warn("Isolated failing update %s@%s", culprit.Dependency.Name, culprit.Dependency.LatestVersion)
+
+		good = append(good, remaining[:hi-1]...)
+		remaining = remaining[hi:]
+		if len(remaining) == 0 {
+			break
+		}
+
+		// The updates after the culprit are only known to pass once tried without it
+		var err error
+		output, err = trial(append(good[:len(good):len(good)], remaining...))
+		if err == nil {
+			good = append(good, remaining...)
+			break
+		}
+	}
+
+	return good, culprits
+}
+
+// finishBatch leaves the project with exactly the passing updates applied
+func (s *Session) finishBatch(good []*models.UpdateAnalysis, result *BatchResult) (*BatchResult, error) {
+	if err := s.applyVerified(good); err != nil {
+		return nil, s.fail(err)
+	}
+
+	result.Applied = good
+	return result, nil
+}
+
+// trial restores the session snapshot, applies the given updates and verifies them
+func (s *Session) trial(analyses []*models.UpdateAnalysis, commands []string) (string, error) {
+	if err := s.snapshot.restore(s.updater.projectPath); err != nil {
+		return "", err
+	}
+
+	s.updater.logger.Print("🔬 Verifying %d updates...", len(analyses))
+
+	if len(analyses) > 0 {
+		if output, err := s.updater.goGet(analyses); err != nil {
+			return output, err
+		}
+
+		if err := s.updater.RunModTidy(); err != nil {
+			return err.Error(), err
+		}
+	}
+
+	return s.updater.Verify(commands)
+}
+
+// applyVerified applies a set of updates that is known to pass verification
+func (s *Session) applyVerified(analyses []*models.UpdateAnalysis) error {
+	if err := s.snapshot.restore(s.updater.projectPath); err != nil {
+		return err
+	}
+
+	s.applied = nil
+	if len(analyses) == 0 {
+		return nil
+	}
+
+	if output, err := s.updater.goGet(analyses); err != nil {
+		return fmt.Errorf("%w\nOutput: %s", err, output)
+	}
+
+	if err := s.updater.RunModTidy(); err != nil {
+		return err
+	}
+
+	for _, analysis := range analyses {
+		dep := analysis.Dependency
+		analysis.Outcome = &models.UpdateOutcome{Applied: true, Verified: true}
+		s.applied = append(s.applied, JournalEntry{
+			Module: dep.Name,
+			From:   dep.CurrentVersion,
+			To:     dep.LatestVersion,
+		})
+		s.updater.logger.Success("Updated %s from %s to %s", dep.Name, dep.CurrentVersion, dep.LatestVersion)
+	}
+
+	return nil
+}
+
+// goGet applies several updates with a single go get invocation
+func (du *DependencyUpdater) goGet(analyses []*models.UpdateAnalysis) (string, error) {
+	args := []string{"get"}
+	for _, analysis := range analyses {
+		args = append(args, analysis.Dependency.Name+"@"+analysis.Dependency.LatestVersion)
+	}
+
+	cmd := exec.Command("go", args...)
+	cmd.Dir = du.projectPath
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(output), fmt.Errorf("go get failed: %w", err)
+	}
+
+	return string(output), nil
+}
@@ -0,0 +1,140 @@
+package dependencies
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+	"github.com/moeryomenko/gupdeps/internal/utils"
+)
+
+func TestBisect(t *testing.T) {
+	tests := []struct {
+		name     string
+		modules  []string
+		bad      []string
+		good     []string
+		culprits []string
+	}{
+		{
+			name:     "first position culprit",
+			modules:  []string{"a", "b", "c"},
+			bad:      []string{"a"},
+			good:     []string{"b", "c"},
+			culprits: []string{"a"},
+		},
+		{
+			name:     "middle culprit",
+			modules:  []string{"a", "b", "c", "d"},
+			bad:      []string{"c"},
+			good:     []string{"a", "b", "d"},
+			culprits: []string{"c"},
+		},
+		{
+			name:     "last position culprit",
+			modules:  []string{"a", "b", "c"},
+			bad:      []string{"c"},
+			good:     []string{"a", "b"},
+			culprits: []string{"c"},
+		},
+		{
+			name:     "multiple culprits",
+			modules:  []string{"a", "b", "c", "d", "e"},
+			bad:      []string{"a", "d"},
+			good:     []string{"b", "c", "e"},
+			culprits: []string{"a", "d"},
+		},
+		{
+			name:     "adjacent culprits",
+			modules:  []string{"a", "b", "c", "d"},
+			bad:      []string{"b", "c"},
+			good:     []string{"a", "d"},
+			culprits: []string{"b", "c"},
+		},
+		{
+			name:     "all bad",
+			modules:  []string{"a", "b", "c"},
+			bad:      []string{"a", "b", "c"},
+			culprits: []string{"a", "b", "c"},
+		},
+		{
+			name:     "single bad update",
+			modules:  []string{"a"},
+			bad:      []string{"a"},
+			culprits: []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyses := make([]*models.UpdateAnalysis, len(tt.modules))
+			for i, module := range tt.modules {
+				analyses[i] = &models.UpdateAnalysis{Dependency: &models.Dependency{Name: module}}
+			}
+
+			output, err := failingVerifier(tt.bad)(analyses)
+			if err == nil {
+				t.Fatal("the whole batch must fail verification")
+			}
+
+			logger := utils.NewLogger(false)
+			logger.SetOutput(io.Discard)
+
+			good, culprits := bisect(analyses, output, failingVerifier(tt.bad), logger)
+			if got := moduleNames(good); !reflect.DeepEqual(got, tt.good) {
+				t.Errorf("good = %v, want %v", got, tt.good)
+			}
+			if got := moduleNames(culprits); !reflect.DeepEqual(got, tt.culprits) {
+				t.Errorf("culprits = %v, want %v", got, tt.culprits)
+			}
+			for _, culprit := range culprits {
+				if want := "broken by " + culprit.Dependency.Name; culprit.Outcome.Output != want {
+					t.Errorf("output of %s = %q, want %q", culprit.Dependency.Name, culprit.Outcome.Output, want)
+				}
+			}
+		})
+	}
+}
+
+// failingVerifier returns a trial that fails when any of the bad modules is
+// updated, with the output naming the last of them
+func failingVerifier(bad []string) trialFunc {
+	return func(analyses []*models.UpdateAnalysis) (string, error) {
+		output := ""
+		for _, analysis := range analyses {
+			for _, module := range bad {
+				if analysis.Dependency.Name == module {
+					output = "broken by " + module
+				}
+			}
+		}
+		if output != "" {
+			return output, errors.New("verification failed")
+		}
+		return "", nil
+	}
+}
+
+// moduleNames returns the module names of the analyses
+func moduleNames(analyses []*models.UpdateAnalysis) []string {
+	var names []string
+	for _, analysis := range analyses {
+		names = append(names, analysis.Dependency.Name)
+	}
+	return names
+}
+
+func TestVerifyQuotedArguments(t *testing.T) {
+	du := &DependencyUpdater{projectPath: t.TempDir()}
+
+	if output, err := du.Verify([]string{`test 'TestA|TestB' = "TestA|TestB"`, "  "}); err != nil {
+		t.Errorf("quoted arguments must reach the command whole: %v\n%s", err, output)
+	}
+
+	output, err := du.Verify([]string{"echo checking", "echo broken >&2; exit 1", "echo never"})
+	if err == nil || output != "broken\n" {
+		t.Errorf("Verify = %q, %v, want the output of the failing command", output, err)
+	}
+}
@@ -0,0 +1,204 @@
+package dependencies
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// changelogFiles lists the file names searched for release notes, in order of preference
+var changelogFiles = []string{"CHANGELOG.md", "CHANGELOG", "CHANGES.md", "HISTORY.md", "RELEASES.md"}
+
+// versionHeading matches a release heading such as "## [1.2.0] - 2024-01-31" or "# v1.2.0 (2024-01-31)"
+var versionHeading = regexp.MustCompile(`^#{1,3}\s+\[?v?(\d+\.\d+\.\d+[^\]\s]*)\]?(?:\s*[-–(]\s*([^)\s]*))?`)
+
+// releaseHeading matches any heading at the level of release headings
+var releaseHeading = regexp.MustCompile(`^#{1,2}\s`)
+
+// groupHeading matches a Keep a Changelog group heading such as "### Fixed" or "Security:"
+var groupHeading = regexp.MustCompile(`(?i)^(?:#{2,4}\s+)?(added|changed|deprecated|removed|fixed|security)\s*:?\s*$`)
+
+// changelogEntry matches a list item
+var changelogEntry = regexp.MustCompile(`^\s*[-*+]\s+(.+)$`)
+
+// readChangelog extracts the changelog sections between the current and the
+// target version from the module at the target version, combined with the
+// annotated tag messages of the releases in between
+func (du *DependencyUpdater) readChangelog(dep *models.Dependency, tagNotes []models.TagNote) *models.Changelog {
+	changelog := &models.Changelog{TagNotes: tagNotes}
+
+	if dir, err := du.fetcher.DownloadModule(dep.Name, dep.LatestVersion); err != nil {
+		du.logger.Info("Could not download %s@%s for its changelog: %v", dep.Name, dep.LatestVersion, err)
+	} else if name, content := findChangelog(dir); name != "" {
+		changelog.Source = name
+		for _, section := range parseChangelog(content, "") {
+			if versionInRange(section.Version, dep.CurrentVersion, dep.LatestVersion) {
+				changelog.Sections = append(changelog.Sections, section)
+			}
+		}
+	}
+
+	// Tag messages fill in the releases the changelog file does not describe
+	fromTags := false
+	for _, note := range tagNotes {
+		version := tagVersion(note.Tag)
+		if hasSection(changelog.Sections, version) {
+			continue
+		}
+		for _, section := range parseChangelog(note.Message, version) {
+			if !versionInRange(section.Version, dep.CurrentVersion, dep.LatestVersion) {
+				continue
+			}
+			changelog.Sections = append(changelog.Sections, section)
+			fromTags = true
+		}
+	}
+
+	if fromTags {
+		changelog.Source = strings.TrimPrefix(changelog.Source+", annotated tags", ", ")
+	}
+
+	return changelog
+}
+
+// findChangelog returns the name and content of the changelog file in a module directory
+func findChangelog(dir string) (name, content string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", ""
+	}
+
+	for _, candidate := range changelogFiles {
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.EqualFold(entry.Name(), candidate) {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+			if err == nil {
+				return entry.Name(), string(data)
+			}
+		}
+	}
+
+	return "", ""
+}
+
+// changelogParser holds the state of a changelog being parsed line by line
+type changelogParser struct {
+	sections []models.ChangelogSection
+	active   bool // false after a heading that is not a released version
+	group    string
+	last     *string // entry continued by wrapped lines
+}
+
+// parseChangelog parses Keep a Changelog formatted text into sections. Entries
+// that precede any version heading belong to defaultVersion, or are dropped
+// when it is empty.
+func parseChangelog(text, defaultVersion string) []models.ChangelogSection {
+	p := &changelogParser{}
+	if defaultVersion != "" {
+		p.startSection(defaultVersion, "")
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		p.parseLine(strings.TrimRight(line, " \t\r"))
+	}
+
+	// Drop the default section when it holds nothing
+	if defaultVersion != "" && len(p.sections[0].Entries) == 0 {
+		return p.sections[1:]
+	}
+
+	return p.sections
+}
+
+// startSection begins the section of a new version
+func (p *changelogParser) startSection(version, date string) {
+	p.sections = append(p.sections, models.ChangelogSection{
+		Version: version,
+		Date:    date,
+		Entries: map[string][]string{},
+	})
+	p.active, p.group, p.last = true, "", nil
+}
+
+// parseLine processes a single line of the changelog
+func (p *changelogParser) parseLine(line string) {
+	if match := versionHeading.FindStringSubmatch(line); match != nil {
+		p.startSection("v"+match[1], match[2])
+		return
+	}
+
+	// Other top-level headings, such as "## [Unreleased]", end the current section
+	if releaseHeading.MatchString(line) {
+		p.active = false
+	}
+
+	if !p.active {
+		return
+	}
+
+	if match := groupHeading.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+		p.group, p.last = canonicalGroup(match[1]), nil
+		return
+	}
+
+	if strings.HasPrefix(line, "#") || p.group == "" {
+		p.group, p.last = "", nil
+		return
+	}
+
+	p.addEntry(line)
+}
+
+// addEntry records a list item of the current group, or continues the previous one
+func (p *changelogParser) addEntry(line string) {
+	section := &p.sections[len(p.sections)-1]
+	if match := changelogEntry.FindStringSubmatch(line); match != nil && !strings.HasPrefix(line, "    ") {
+		section.Entries[p.group] = append(section.Entries[p.group], match[1])
+		p.last = &section.Entries[p.group][len(section.Entries[p.group])-1]
+	} else if trimmed := strings.TrimSpace(line); trimmed != "" && p.last != nil {
+		*p.last += " " + trimmed // continuation of a wrapped entry
+	}
+}
+
+// canonicalGroup returns the canonical spelling of a changelog group name
+func canonicalGroup(name string) string {
+	for _, group := range models.ChangelogGroups {
+		if strings.EqualFold(group, name) {
+			return group
+		}
+	}
+	return name
+}
+
+// hasSection reports whether the sections already describe a version
+func hasSection(sections []models.ChangelogSection, version string) bool {
+	for _, section := range sections {
+		if semver.Compare(section.Version, version) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// tagVersion returns the semantic version of a tag, stripping a module subdirectory prefix
+func tagVersion(tag string) string {
+	version := tag[strings.LastIndex(tag, "/")+1:]
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	return version
+}
+
+// versionInRange reports whether from < version <= to
+func versionInRange(version, from, to string) bool {
+	return semver.IsValid(version) &&
+		semver.Compare(version, from) > 0 &&
+		semver.Compare(version, to) <= 0
+}
@@ -0,0 +1,32 @@
+package dependencies
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// conventionalHeader matches a Conventional Commits header: type(scope)!: description
+var conventionalHeader = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^()]*)\))?(!)?: \S`)
+
+// breakingFooter matches a BREAKING CHANGE footer in the commit body
+var breakingFooter = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
+
+// parseConventionalCommit fills the Conventional Commits fields of a commit.
+// It reports whether the commit message follows the specification.
+func parseConventionalCommit(commit *models.CommitInfo) bool {
+	header, body, _ := strings.Cut(commit.Message, "\n")
+
+	match := conventionalHeader.FindStringSubmatch(header)
+	if match == nil {
+		return false
+	}
+
+	commit.Conventional = true
+	commit.Type = strings.ToLower(match[1])
+	commit.Scope = match[2]
+	commit.Breaking = match[3] == "!" || breakingFooter.MatchString(body)
+
+	return true
+}
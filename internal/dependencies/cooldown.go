@@ -0,0 +1,69 @@
+package dependencies
+
+import (
+	"time"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// applyCooldown holds back releases younger than the minimum release age of the
+// module and proposes the newest mature version instead. Releases fixing a
+// vulnerability that affects the mature version are exempt.
+func (du *DependencyUpdater) applyCooldown(dep *models.Dependency) {
+	dep.Pending = nil
+	days := du.cfg.Cooldown.For(dep.Name)
+	if days == 0 || !dep.UpdateNeeded {
+		return
+	}
+
+	times, err := du.fetcher.GetVersionTimes(dep.Name, dep.Versions...)
+	if err != nil {
+		du.logger.Warn("Could not determine release times of %s: %v", dep.Name, err)
+		return
+	}
+
+	latestReleased := times[dep.LatestVersion]
+	if latestReleased.IsZero() {
+		return
+	}
+
+	minAge := time.Duration(days) * 24 * time.Hour
+	mature := dep.CurrentVersion
+	for _, version := range dep.Versions {
+		if released := times[version]; !released.IsZero() && time.Since(released) >= minAge {
+			mature = version
+		}
+	}
+
+	if mature == dep.LatestVersion {
+		return
+	}
+
+	if du.fixesVulnerability(dep.Name, mature, dep.LatestVersion) {
+		du.logger.Info("%s@%s is younger than %d days but fixes a vulnerability", dep.Name, dep.LatestVersion, days)
+		return
+	}
+
+	dep.Pending = &models.PendingVersion{
+		Version: dep.LatestVersion,
+		Until:   latestReleased.Add(minAge),
+	}
+	dep.LatestVersion = mature
+	dep.UpdateNeeded = mature != dep.CurrentVersion
+}
+
+// fixesVulnerability reports whether a version is free of the known
+// vulnerabilities affecting an older one
+func (du *DependencyUpdater) fixesVulnerability(module, older, newer string) bool {
+	if du.vulnDB == nil {
+		return false
+	}
+
+	affected, err := du.vulnDB.Affects(module, older)
+	if err != nil || !affected {
+		return false
+	}
+
+	stillAffected, err := du.vulnDB.Affects(module, newer)
+	return err == nil && !stillAffected
+}
@@ -12,6 +12,8 @@ import (
 	"strings"
 	"time"
 
+	"golang.org/x/mod/semver"
+
 	"github.com/moeryomenko/gupdeps/internal/models"
 	"github.com/moeryomenko/gupdeps/internal/utils"
 )
@@ -206,5 +208,92 @@ func (df *DependencyFetcher) GetLatestVersion(dep *models.Dependency) error {
 		dep.UpdateNeeded = dep.CurrentVersion != dep.LatestVersion
 	}
 
+	dep.Versions = nil
+	for _, version := range versions {
+		if semver.Compare(version, dep.CurrentVersion) > 0 {
+			dep.Versions = append(dep.Versions, version)
+		}
+	}
+
 	return nil
 }
+
+// GetRetraction records the rationale when the current version of a dependency
+// has been retracted by its author
+func (df *DependencyFetcher) GetRetraction(dep *models.Dependency) error {
+	cmd := exec.Command("go", "list", "-m", "-retracted", "-json", dep.Name+"@"+dep.CurrentVersion)
+	cmd.Dir = df.projectPath
+
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to get retractions of %s: %w", dep.Name, err)
+	}
+
+	var info struct {
+		Retracted []string `json:"Retracted"`
+	}
+	if err := json.Unmarshal(output, &info); err != nil {
+		return fmt.Errorf("failed to decode retractions of %s: %w", dep.Name, err)
+	}
+
+	dep.Retracted = info.Retracted
+	return nil
+}
+
+// GetVersionTimes returns the publication time of the given versions of a module
+func (df *DependencyFetcher) GetVersionTimes(module string, versions ...string) (map[string]time.Time, error) {
+	args := []string{"list", "-m", "-json"}
+	for _, version := range versions {
+		args = append(args, module+"@"+version)
+	}
+
+	cmd := exec.Command("go", args...)
+	cmd.Dir = df.projectPath
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version info for %s: %w", module, err)
+	}
+
+	times := make(map[string]time.Time, len(versions))
+	decoder := json.NewDecoder(strings.NewReader(string(output)))
+	for {
+		var info struct {
+			Version string    `json:"Version"`
+			Time    time.Time `json:"Time"`
+		}
+
+		if err := decoder.Decode(&info); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to decode version info for %s: %w", module, err)
+		}
+
+		times[info.Version] = info.Time
+	}
+
+	return times, nil
+}
+
+// DownloadModule downloads a module version into the module cache and returns its directory
+func (df *DependencyFetcher) DownloadModule(module, version string) (string, error) {
+	cmd := exec.Command("go", "mod", "download", "-json", module+"@"+version)
+	// Run outside the project so its go.mod and go.sum are left untouched
+	cmd.Dir = os.TempDir()
+	cmd.Env = append(os.Environ(), "GOWORK=off")
+
+	output, err := cmd.Output()
+
+	var info struct {
+		Dir   string `json:"Dir"`
+		Error string `json:"Error"`
+	}
+	if decodeErr := json.Unmarshal(output, &info); decodeErr == nil && info.Error != "" {
+		return "", fmt.Errorf("failed to download %s@%s: %s", module, version, info.Error)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s@%s: %w", module, version, err)
+	}
+
+	return info.Dir, nil
+}
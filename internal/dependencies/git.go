@@ -1,47 +1,51 @@
 package dependencies
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
+	gomodule "golang.org/x/mod/module"
+
 	"github.com/moeryomenko/gupdeps/internal/models"
 	"github.com/moeryomenko/gupdeps/internal/utils"
 )
 
 // GitOperations handles Git-related operations
 type GitOperations struct {
-	logger *utils.Logger
+	cacheDir string // keeps clones between runs when set
+	logger   *utils.Logger
+
+	mu     sync.Mutex
+	clones map[string]string // module -> temporary clone kept until Close, nil when not kept
 }
 
 // NewGitOperations creates a new GitOperations instance
-func NewGitOperations(logger *utils.Logger) *GitOperations {
+func NewGitOperations(cacheDir string, logger *utils.Logger) *GitOperations {
 	return &GitOperations{
-		logger: logger,
+		cacheDir: cacheDir,
+		logger:   logger,
 	}
 }
 
-// GetCommitsBetweenVersions fetches commits between two versions
-func (g *GitOperations) GetCommitsBetweenVersions(dep *models.Dependency) ([]models.CommitInfo, error) {
+// GetCommitsBetweenVersions fetches commits and annotated tag messages between two versions
+func (g *GitOperations) GetCommitsBetweenVersions(dep *models.Dependency) ([]models.CommitInfo, []models.TagNote, error) {
 	if dep.CurrentVersion == dep.LatestVersion {
-		return []models.CommitInfo{}, nil
+		return []models.CommitInfo{}, nil, nil
 	}
 
-	// Create a temporary directory for the repository
-	tempDir, err := os.MkdirTemp("", "dependency-*")
+	tempDir, cleanup, err := g.repositoryDir(dep.Name)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create temp directory: %w", err)
-	}
-	defer os.RemoveAll(tempDir) // Clean up when done
-
-	repoURL := g.determineRepositoryURL(dep.Name)
-
-	// Clone the repository with minimal depth
-	if err := g.cloneRepository(repoURL, tempDir); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	defer cleanup()
 
 	// Try to fetch tags
 	g.fetchTags(tempDir, dep)
@@ -49,11 +53,227 @@ func (g *GitOperations) GetCommitsBetweenVersions(dep *models.Dependency) ([]mod
 	// Get commits between versions
 	commits, err := g.getCommitLog(tempDir, dep)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	g.logger.Info("Found %d commits between versions for %s", len(commits), dep.Name)
-	return commits, nil
+	return commits, g.getTagNotes(tempDir, dep), nil
+}
+
+// KeepClones keeps the temporary clones for reuse until Close, so the
+// commits of an analyzed update can be browsed without cloning again
+func (g *GitOperations) KeepClones() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.clones == nil {
+		g.clones = make(map[string]string)
+	}
+}
+
+// Close removes the temporary clones kept and stops keeping them
+func (g *GitOperations) Close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, dir := range g.clones {
+		os.RemoveAll(dir)
+	}
+	g.clones = nil
+}
+
+// repositoryDir returns a clone of the module's repository. Without a cache
+// directory the clone is temporary and removed by the cleanup function, or
+// by Close while clones are kept.
+func (g *GitOperations) repositoryDir(module string) (dir string, cleanup func(), err error) {
+	repoURL := g.determineRepositoryURL(module)
+
+	if g.cacheDir == "" {
+		if dir, ok := g.keptClone(module); ok {
+			return dir, func() {}, nil
+		}
+
+		// Create a temporary directory for the repository
+		dir, err = os.MkdirTemp("", "dependency-*")
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to create temp directory: %w", err)
+		}
+		cleanup = func() { os.RemoveAll(dir) }
+
+		// Clone the repository with minimal depth
+		if err := g.cloneRepository(repoURL, dir); err != nil {
+			cleanup()
+			return "", nil, err
+		}
+		if g.keepClone(module, dir) {
+			return dir, func() {}, nil
+		}
+		return dir, cleanup, nil
+	}
+
+	// Cached clones are refreshed by the tag fetch that follows
+	dir = filepath.Join(g.cacheDir, "repos", repositoryCacheKey(repoURL))
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		return dir, func() {}, nil
+	}
+
+	if err := g.cloneIntoCache(repoURL, dir); err != nil {
+		return "", nil, err
+	}
+
+	return dir, func() {}, nil
+}
+
+// repositoryCacheKey names the cached clone of a repository after its URL, so
+// modules sharing a repository share the clone and clones never nest
+func repositoryCacheKey(repoURL string) string {
+	sum := sha256.Sum256([]byte(repoURL))
+	name := strings.TrimSuffix(path.Base(repoURL), ".git")
+	return name + "-" + hex.EncodeToString(sum[:8])
+}
+
+// cloneIntoCache clones a repository next to its cache directory and moves the
+// clone into place, keeping a clone another analysis moved there meanwhile
+func (g *GitOperations) cloneIntoCache(repoURL, dir string) error {
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tempDir, err := os.MkdirTemp(filepath.Dir(dir), ".clone-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := g.cloneRepository(repoURL, tempDir); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tempDir, dir); err != nil {
+		if _, statErr := os.Stat(filepath.Join(dir, ".git")); statErr == nil {
+			return nil
+		}
+		return fmt.Errorf("failed to move clone into the cache: %w", err)
+	}
+	return nil
+}
+
+// keptClone returns the temporary clone kept for a module
+func (g *GitOperations) keptClone(module string) (string, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	dir, ok := g.clones[module]
+	return dir, ok
+}
+
+// keepClone records a temporary clone for reuse and reports whether clones are kept
+func (g *GitOperations) keepClone(module, dir string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.clones == nil {
+		return false
+	}
+	if previous, ok := g.clones[module]; ok {
+		// Another analysis of the module cloned it meanwhile
+		os.RemoveAll(previous)
+	}
+	g.clones[module] = dir
+	return true
+}
+
+// GetCommitDiff returns the stat and patch of a commit, limited to the
+// directory of the module within its repository
+func (g *GitOperations) GetCommitDiff(modulePath, hash string) (string, error) {
+	repoDir, cleanup, err := g.repositoryDir(modulePath)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	// Shallow clones lack the commit or its parent until they are fetched
+	parentCmd := exec.Command("git", "rev-parse", "--verify", "--quiet", hash+"^")
+	parentCmd.Dir = repoDir
+	if err := parentCmd.Run(); err != nil {
+		fetchCmd := exec.Command("git", "fetch", "--quiet", "--depth=2", "origin", hash)
+		fetchCmd.Dir = repoDir
+		if output, err := fetchCmd.CombinedOutput(); err != nil {
+			return "", fmt.Errorf("failed to fetch commit %s: %w\nOutput: %s", hash, err, string(output))
+		}
+	}
+
+	args := []string{"show", "--stat", "--patch", "--format=fuller", hash}
+	if dir := moduleDir(repoDir, modulePath, hash); dir != "" {
+		args = append(args, "--", dir)
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to show commit %s: %w\nOutput: %s", hash, err, string(output))
+	}
+
+	return string(output), nil
+}
+
+// moduleDir returns the directory of a module within its repository at a
+// commit, empty when the module is at the repository root
+func moduleDir(repoDir, modulePath, hash string) string {
+	parts := strings.SplitN(modulePath, "/", 4)
+	if _, known := commitPaths[parts[0]]; !known || len(parts) < 4 {
+		return ""
+	}
+
+	// A major version suffix is either a subdirectory or only part of the module path
+	prefix, _, _ := gomodule.SplitPathVersion(modulePath)
+	for _, candidate := range []string{modulePath, prefix} {
+		dir := strings.TrimPrefix(candidate, strings.Join(parts[:3], "/"))
+		dir = strings.TrimPrefix(dir, "/")
+		if dir == "" {
+			return ""
+		}
+
+		cmd := exec.Command("git", "cat-file", "-e", hash+":"+dir)
+		cmd.Dir = repoDir
+		if cmd.Run() == nil {
+			return dir
+		}
+	}
+
+	return ""
+}
+
+// getTagNotes returns the messages of annotated tags between versions, oldest first
+func (g *GitOperations) getTagNotes(repoDir string, dep *models.Dependency) []models.TagNote {
+	cmd := exec.Command("git", "for-each-ref", "--sort=v:refname",
+		"--format=%(refname:short)%1f%(objecttype)%1f%(contents:subject)%0a%0a%(contents:body)%1e", "refs/tags")
+	cmd.Dir = repoDir
+
+	output, err := cmd.Output()
+	if err != nil {
+		g.logger.Info("Could not list tags of %s: %v", dep.Name, err)
+		return nil
+	}
+
+	var notes []models.TagNote
+	for _, record := range strings.Split(string(output), "\x1e") {
+		parts := strings.SplitN(strings.TrimLeft(record, "\n"), "\x1f", 3)
+		if len(parts) < 3 || parts[1] != "tag" {
+			continue // lightweight tags carry no message
+		}
+
+		if !versionInRange(tagVersion(parts[0]), dep.CurrentVersion, dep.LatestVersion) {
+			continue
+		}
+
+		if message := strings.TrimSpace(parts[2]); message != "" {
+			notes = append(notes, models.TagNote{Tag: parts[0], Message: message})
+		}
+	}
+
+	return notes
 }
 
 // determineRepositoryURL derives the git repository URL from the module path
@@ -90,6 +310,29 @@ func (g *GitOperations) determineRepositoryURL(modulePath string) string {
 	return "https://" + modulePath + ".git"
 }
 
+// commitPaths maps the repository hosts with a known web UI to the path of a commit page
+var commitPaths = map[string]string{
+	"github.com":    "/commit/",
+	"gitlab.com":    "/-/commit/",
+	"bitbucket.org": "/commits/",
+}
+
+// CommitURL returns the web page of a commit in a module's repository, or an
+// empty string when the repository host is not known
+func CommitURL(modulePath, hash string) string {
+	parts := strings.SplitN(modulePath, "/", 4)
+	if len(parts) < 3 {
+		return ""
+	}
+
+	commitPath, ok := commitPaths[parts[0]]
+	if !ok {
+		return ""
+	}
+
+	return "https://" + strings.Join(parts[:3], "/") + commitPath + hash
+}
+
 // cloneRepository clones the git repository with minimal configuration
 func (g *GitOperations) cloneRepository(repoURL, destDir string) error {
 	cloneCmd := exec.Command("git", "clone",
@@ -201,9 +444,13 @@ func (g *GitOperations) getCommitLog(repoDir string, dep *models.Dependency) ([]
 	return g.parseCommitLog(output), nil
 }
 
+// commitLogFormat separates commit fields with US and commits with RS, so
+// multi-line bodies (and their footers) survive parsing
+const commitLogFormat = "--pretty=format:%H%x1f%s%x1f%aI%x1f%aN%x1f%b%x1e"
+
 // runGitLog executes git log with the specified version range
 func (g *GitOperations) runGitLog(repoDir, fromVersion, toVersion string) ([]byte, error) {
-	cmd := exec.Command("git", "log", "--pretty=format:%H|%s|%aI|%aN|%b",
+	cmd := exec.Command("git", "log", commitLogFormat,
 		fmt.Sprintf("%s..%s", fromVersion, toVersion))
 	cmd.Dir = repoDir
 	return cmd.Output()
@@ -211,7 +458,7 @@ func (g *GitOperations) runGitLog(repoDir, fromVersion, toVersion string) ([]byt
 
 // getAllCommits gets a limited number of recent commits
 func (g *GitOperations) getAllCommits(repoDir string) ([]byte, error) {
-	cmd := exec.Command("git", "log", "--pretty=format:%H|%s|%aI|%aN|%b", "-n", "300")
+	cmd := exec.Command("git", "log", commitLogFormat, "-n", "300")
 	cmd.Dir = repoDir
 	return cmd.Output()
 }
@@ -219,12 +466,13 @@ func (g *GitOperations) getAllCommits(repoDir string) ([]byte, error) {
 // parseCommitLog parses the git log output into CommitInfo structs
 func (g *GitOperations) parseCommitLog(output []byte) []models.CommitInfo {
 	commits := []models.CommitInfo{}
-	lines := strings.Split(string(output), "\n")
+	records := strings.Split(string(output), "\x1e")
 	maxCommits := 200
 	processedCommits := 0
 
-	for _, line := range lines {
-		if line == "" {
+	for _, record := range records {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
 			continue
 		}
 
@@ -234,7 +482,7 @@ func (g *GitOperations) parseCommitLog(output []byte) []models.CommitInfo {
 		}
 		processedCommits++
 
-		parts := strings.SplitN(line, "|", 5)
+		parts := strings.SplitN(record, "\x1f", 5)
 		if len(parts) < 4 { // We need at least hash, message, date, and author
 			continue
 		}
@@ -245,8 +493,8 @@ func (g *GitOperations) parseCommitLog(output []byte) []models.CommitInfo {
 
 		// Extract full commit message if available
 		fullMessage := message
-		if len(parts) >= 5 && parts[4] != "" {
-			fullMessage = message + "\n\n" + parts[4]
+		if len(parts) >= 5 && strings.TrimSpace(parts[4]) != "" {
+			fullMessage = message + "\n\n" + strings.TrimSpace(parts[4])
 		}
 
 		date, err := g.parseCommitDate(dateStr)
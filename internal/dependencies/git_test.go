@@ -0,0 +1,74 @@
+package dependencies
+
+import (
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moeryomenko/gupdeps/internal/utils"
+)
+
+func TestCachedRepositoryDir(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	// Serve both major versions from local repositories
+	gitConfig := filepath.Join(t.TempDir(), "gitconfig")
+	config := "[protocol \"file\"]\n\tallow = always\n"
+	for _, module := range []string{"github.com/x/y/v2", "github.com/x/y"} {
+		repo := initTestRepository(t, module)
+		config += "[url \"" + repo + "\"]\n\tinsteadOf = https://" + module + ".git\n"
+	}
+	writeTestFile(t, filepath.Dir(gitConfig), "gitconfig", config)
+	t.Setenv("GIT_CONFIG_GLOBAL", gitConfig)
+
+	logger := utils.NewLogger(false)
+	logger.SetOutput(io.Discard)
+	g := NewGitOperations(t.TempDir(), logger)
+
+	// The nested module path is cloned first
+	dirs := make(map[string]string)
+	for _, module := range []string{"github.com/x/y/v2", "github.com/x/y", "github.com/x/y/v2"} {
+		dir, cleanup, err := g.repositoryDir(module)
+		if err != nil {
+			t.Fatalf("%s: %v", module, err)
+		}
+		cleanup()
+
+		if previous, ok := dirs[module]; ok && previous != dir {
+			t.Errorf("%s was cloned again into %s", module, dir)
+		}
+		dirs[module] = dir
+		if content := readFile(dir, "module.txt"); content != module {
+			t.Errorf("clone of %s holds %q", module, content)
+		}
+	}
+
+	v1, v2 := dirs["github.com/x/y"], dirs["github.com/x/y/v2"]
+	if strings.HasPrefix(v2, v1+string(filepath.Separator)) || strings.HasPrefix(v1, v2+string(filepath.Separator)) {
+		t.Errorf("clones %s and %s are nested", v1, v2)
+	}
+}
+
+// initTestRepository creates a repository with one commit naming the module
+func initTestRepository(t *testing.T, module string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	writeTestFile(t, dir, "module.txt", module)
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", args[0], err, output)
+		}
+	}
+	return dir
+}
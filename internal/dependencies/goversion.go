@@ -0,0 +1,168 @@
+package dependencies
+
+import (
+	"fmt"
+	"go/version"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// factorGoVersion is the risk factor of raised go and toolchain directives
+const factorGoVersion = "go-version"
+
+// checkGoRequirement compares the go and toolchain directives of the dependency's
+// go.mod at the current and target versions
+func (du *DependencyUpdater) checkGoRequirement(analysis *models.UpdateAnalysis) {
+	dep := analysis.Dependency
+
+	oldDir, err := du.fetcher.DownloadModule(dep.Name, dep.CurrentVersion)
+	if err != nil {
+		du.logger.Info("Could not read go.mod of %s@%s: %v", dep.Name, dep.CurrentVersion, err)
+		return
+	}
+
+	newDir, err := du.fetcher.DownloadModule(dep.Name, dep.LatestVersion)
+	if err != nil {
+		du.logger.Info("Could not read go.mod of %s@%s: %v", dep.Name, dep.LatestVersion, err)
+		return
+	}
+
+	requirement := &models.GoRequirement{}
+	requirement.From, requirement.FromToolchain = readGoDirectives(filepath.Join(oldDir, "go.mod"))
+	requirement.To, requirement.ToToolchain = readGoDirectives(filepath.Join(newDir, "go.mod"))
+	analysis.GoRequirement = requirement
+}
+
+// decideOnGoRequirement rejects updates requiring a newer Go than the configured
+// maximum or than the project's go directive
+func (du *DependencyUpdater) decideOnGoRequirement(analysis *models.UpdateAnalysis) {
+	requirement := analysis.GoRequirement
+	if requirement == nil || !analysis.ShouldUpdate {
+		return
+	}
+
+	if limit := du.cfg.ForModule(analysis.Dependency.Name).Go.MaxVersion; limit != "" {
+		required := maxGoVersion(requirement.To, toolchainVersion(requirement.ToToolchain))
+		if exceedsGoLimit(required, limit) {
+			analysis.ShouldUpdate = false
+			analysis.RejectionReason = fmt.Sprintf("Requires Go %s, above the maximum Go %s", required, limit)
+			return
+		}
+	}
+
+	if project := du.projectGoVersion(); project != "" && compareGoVersions(requirement.To, project) > 0 {
+		analysis.ShouldUpdate = false
+		analysis.RejectionReason = fmt.Sprintf("Requires Go %s, above go %s of the project's go.mod", requirement.To, project)
+	}
+}
+
+// goVersionFactor scores raised go and toolchain directives, the more so when
+// the new go directive forces the project to a newer Go
+func goVersionFactor(requirement *models.GoRequirement, project string) (models.RiskFactor, bool) {
+	if requirement == nil {
+		return models.RiskFactor{}, false
+	}
+
+	factor := models.RiskFactor{Name: factorGoVersion}
+	var details []string
+
+	if compareGoVersions(requirement.To, requirement.From) > 0 {
+		factor.Points += 5
+		details = append(details, "go "+goVersionName(requirement.From)+" → "+requirement.To)
+		if project != "" && compareGoVersions(requirement.To, project) > 0 {
+			factor.Points += 20
+			details = append(details, "above go "+project+" of the project")
+		}
+	}
+
+	if compareGoVersions(toolchainVersion(requirement.ToToolchain), toolchainVersion(requirement.FromToolchain)) > 0 {
+		factor.Points += 5
+		details = append(details, "toolchain "+goVersionName(requirement.FromToolchain)+" → "+requirement.ToToolchain)
+	}
+
+	if len(details) == 0 {
+		return models.RiskFactor{}, false
+	}
+
+	factor.Detail = strings.Join(details, ", ")
+	return factor, true
+}
+
+// projectGoVersion returns the go directive of the project's go.mod
+func (du *DependencyUpdater) projectGoVersion() string {
+	goVersion, _ := readGoDirectives(filepath.Join(du.projectPath, "go.mod"))
+	return goVersion
+}
+
+// readGoDirectives returns the go and toolchain directives of a go.mod file;
+// a missing file or directive yields an empty value
+func readGoDirectives(path string) (goVersion, toolchain string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", ""
+	}
+
+	file, err := modfile.ParseLax(path, data, nil)
+	if err != nil {
+		return "", ""
+	}
+
+	if file.Go != nil {
+		goVersion = file.Go.Version
+	}
+	if file.Toolchain != nil {
+		toolchain = file.Toolchain.Name
+	}
+
+	return goVersion, toolchain
+}
+
+// toolchainVersion returns the Go version of a toolchain name such as go1.22.3
+func toolchainVersion(toolchain string) string {
+	return strings.TrimPrefix(toolchain, "go")
+}
+
+// exceedsGoLimit reports whether a Go version is above a limit; a limit without
+// a patch release, such as 1.22, admits all of its patch releases
+func exceedsGoLimit(v, limit string) bool {
+	if lang := "go" + limit; version.Lang(lang) == lang {
+		return version.Compare(version.Lang("go"+v), lang) > 0
+	}
+	return compareGoVersions(v, limit) > 0
+}
+
+// maxGoVersion returns the newer of two Go versions
+func maxGoVersion(a, b string) string {
+	if compareGoVersions(a, b) >= 0 {
+		return a
+	}
+	return b
+}
+
+// compareGoVersions compares Go versions such as 1.22 and 1.22.3, treating a
+// language version as its first release; invalid or empty versions sort first
+func compareGoVersions(a, b string) int {
+	return version.Compare(goRelease(a), goRelease(b))
+}
+
+// goRelease converts a go directive value to a comparable release name
+func goRelease(v string) string {
+	release := "go" + v
+	if version.Lang(release) == release && version.Compare(release, "go1.21") >= 0 {
+		release += ".0" // since Go 1.21 "go 1.22" means the go1.22.0 release
+	}
+	return release
+}
+
+// goVersionName returns a printable go or toolchain directive
+func goVersionName(v string) string {
+	if v == "" {
+		return "none"
+	}
+	return v
+}
@@ -0,0 +1,173 @@
+package dependencies
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// factorGraphGrowth is the risk factor of modules added to the module graph
+const factorGraphGrowth = "graph-growth"
+
+// diffModuleGraph resolves the module graph before and after an update in a
+// scratch copy of the project's module files, leaving the project untouched
+func (du *DependencyUpdater) diffModuleGraph(dep *models.Dependency) (*models.ModuleGraphDiff, error) {
+	dir, err := os.MkdirTemp("", "gupdeps-graph-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := copyModuleFiles(du.projectPath, dir); err != nil {
+		return nil, err
+	}
+
+	before, err := du.listModules(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if output, err := runGo(dir, "get", dep.Name+"@"+dep.LatestVersion); err != nil {
+		return nil, fmt.Errorf("go get failed: %w\nOutput: %s", err, output)
+	}
+
+	after, err := du.listModules(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// The updated module itself is not churn
+	delete(before, dep.Name)
+	delete(after, dep.Name)
+
+	return diffModuleVersions(before, after), nil
+}
+
+// copyModuleFiles copies go.mod and go.sum into a scratch directory
+func copyModuleFiles(projectPath, dir string) error {
+	goMod, err := relocatedGoMod(projectPath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), goMod, 0o644); err != nil {
+		return fmt.Errorf("failed to write scratch go.mod: %w", err)
+	}
+
+	sum, err := os.ReadFile(filepath.Join(projectPath, "go.sum"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read go.sum: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "go.sum"), sum, 0o644); err != nil {
+		return fmt.Errorf("failed to write scratch go.sum: %w", err)
+	}
+
+	return nil
+}
+
+// relocatedGoMod returns the project's go.mod with local replacements rewritten
+// to absolute paths, so they still resolve from another directory
+func relocatedGoMod(projectPath string) ([]byte, error) {
+	goModPath := filepath.Join(projectPath, "go.mod")
+	data, err := os.ReadFile(goModPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod: %w", err)
+	}
+
+	file, err := modfile.Parse(goModPath, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse go.mod: %w", err)
+	}
+
+	absProject, err := filepath.Abs(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project path: %w", err)
+	}
+
+	for _, replace := range file.Replace {
+		if replace.New.Version != "" || filepath.IsAbs(replace.New.Path) {
+			continue
+		}
+		target := filepath.Join(absProject, replace.New.Path)
+		if err := file.AddReplace(replace.Old.Path, replace.Old.Version, target, ""); err != nil {
+			return nil, fmt.Errorf("failed to rewrite replacement of %s: %w", replace.Old.Path, err)
+		}
+	}
+
+	data, err = file.Format()
+	if err != nil {
+		return nil, fmt.Errorf("failed to format go.mod: %w", err)
+	}
+
+	return data, nil
+}
+
+// listModules returns the selected version of every module in the graph
+func (du *DependencyUpdater) listModules(dir string) (map[string]string, error) {
+	output, err := runGo(dir, "list", "-m", "-json", "all")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list modules: %w\nOutput: %s", err, output)
+	}
+
+	return du.fetcher.parseModuleVersions(output), nil
+}
+
+// runGo runs a go command ignoring any workspace and vendor directory, so that
+// scratch module files take effect
+func runGo(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod")
+	return cmd.CombinedOutput()
+}
+
+// diffModuleVersions compares two module graphs
+func diffModuleVersions(before, after map[string]string) *models.ModuleGraphDiff {
+	diff := &models.ModuleGraphDiff{}
+
+	for path, from := range before {
+		to, ok := after[path]
+		change := models.ModuleChange{Path: path, From: from, To: to}
+		switch {
+		case !ok:
+			diff.Removed = append(diff.Removed, change)
+		case semver.Compare(to, from) > 0:
+			diff.Upgraded = append(diff.Upgraded, change)
+		case semver.Compare(to, from) < 0:
+			diff.Downgraded = append(diff.Downgraded, change)
+		}
+	}
+
+	for path, to := range after {
+		if _, ok := before[path]; !ok {
+			diff.Added = append(diff.Added, models.ModuleChange{Path: path, To: to})
+		}
+	}
+
+	for _, changes := range [][]models.ModuleChange{diff.Added, diff.Removed, diff.Upgraded, diff.Downgraded} {
+		sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	}
+
+	return diff
+}
+
+// graphGrowthFactor scores the modules an update adds beyond the allowed growth
+func graphGrowthFactor(diff *models.ModuleGraphDiff, allowed int) models.RiskFactor {
+	return models.RiskFactor{
+		Name:   factorGraphGrowth,
+		Points: min(max(len(diff.Added)-allowed, 0)*3, 30),
+		Detail: fmt.Sprintf("%d added, %d removed, %d upgraded, %d downgraded modules",
+			len(diff.Added), len(diff.Removed), len(diff.Upgraded), len(diff.Downgraded)),
+	}
+}
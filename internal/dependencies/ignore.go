@@ -0,0 +1,46 @@
+package dependencies
+
+import (
+	"time"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// applyIgnoreList skips the versions excluded by the ignore list and proposes
+// the newest remaining version instead
+func (du *DependencyUpdater) applyIgnoreList(dep *models.Dependency) {
+	dep.Ignored = nil
+	if !dep.UpdateNeeded {
+		return
+	}
+
+	now := time.Now()
+	var allowed []string
+	var ignored *models.IgnoredVersion
+	for _, version := range dep.Versions {
+		entry := du.cfg.Ignore.Match(dep.Name, version, now)
+		if entry == nil {
+			allowed = append(allowed, version)
+		} else {
+			ignored = &models.IgnoredVersion{Version: version, Reason: entry.Describe()}
+		}
+	}
+
+	if ignored == nil {
+		return
+	}
+
+	dep.Versions = allowed
+	dep.LatestVersion = dep.CurrentVersion
+	if len(allowed) > 0 {
+		dep.LatestVersion = allowed[len(allowed)-1]
+	}
+	dep.UpdateNeeded = dep.LatestVersion != dep.CurrentVersion
+
+	// Ignored versions older than the proposed one are not worth reporting
+	if semver.Compare(ignored.Version, dep.LatestVersion) > 0 {
+		dep.Ignored = ignored
+	}
+}
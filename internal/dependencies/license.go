@@ -0,0 +1,224 @@
+package dependencies
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// licenseFilePrefixes lists the upper-cased name prefixes of license files
+var licenseFilePrefixes = []string{"LICENSE", "LICENCE", "COPYING", "UNLICENSE"}
+
+// spdxHeader matches an SPDX-License-Identifier tag
+var spdxHeader = regexp.MustCompile(`(?m)SPDX-License-Identifier:\s*([^\r\n*]+?)\s*(?:\*/|-->|$)`)
+
+// licenseTitle identifies a license by its title, which opens its text
+type licenseTitle struct {
+	id    string
+	title string // normalized title, with the version
+}
+
+// licenseTitles lists the licenses whose texts mention other licenses, so
+// only the title found first in the opening of a text identifies it
+var licenseTitles = []licenseTitle{
+	{id: "AGPL-3.0", title: "gnu affero general public license version 3"},
+	{id: "LGPL-3.0", title: "gnu lesser general public license version 3"},
+	{id: "LGPL-2.1", title: "gnu lesser general public license version 2.1"},
+	{id: "LGPL-2.0", title: "gnu library general public license version 2"},
+	{id: "GPL-3.0", title: "gnu general public license version 3"},
+	{id: "GPL-2.0", title: "gnu general public license version 2"},
+	{id: "MPL-2.0", title: "mozilla public license version 2.0"},
+	{id: "Apache-2.0", title: "apache license version 2.0"},
+	{id: "SSPL-1.0", title: "server side public license"},
+	{id: "BUSL-1.1", title: "business source license"},
+	{id: "Elastic-2.0", title: "elastic license 2.0"},
+}
+
+// titleWindow is how far into a normalized text a license title is looked
+// for, leaving room for copyright lines above it
+const titleWindow = 1000
+
+// licenseSignature identifies an untitled license by phrases of its text
+type licenseSignature struct {
+	id      string
+	phrases []string // all must appear in the normalized text
+}
+
+// licenseSignatures are checked in order, the first match wins
+var licenseSignatures = []licenseSignature{
+	{id: "BSD-3-Clause", phrases: []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{id: "BSD-2-Clause", phrases: []string{"redistribution and use in source and binary forms"}},
+	{id: "MIT", phrases: []string{"permission is hereby granted free of charge"}},
+	{id: "ISC", phrases: []string{"permission to use copy modify and or distribute this software for any purpose",
+		"provided that the above copyright notice"}},
+	{id: "0BSD", phrases: []string{"permission to use copy modify and or distribute this software for any purpose"}},
+	{id: "Unlicense", phrases: []string{"free and unencumbered software released into the public domain"}},
+}
+
+// checkLicense identifies the license at both versions and rejects updates
+// moving to a license outside the allow-list
+func (du *DependencyUpdater) checkLicense(analysis *models.UpdateAnalysis) {
+	dep := analysis.Dependency
+
+	oldDir, err := du.fetcher.DownloadModule(dep.Name, dep.CurrentVersion)
+	if err != nil {
+		du.logger.Info("Could not determine license of %s@%s: %v", dep.Name, dep.CurrentVersion, err)
+		return
+	}
+
+	newDir, err := du.fetcher.DownloadModule(dep.Name, dep.LatestVersion)
+	if err != nil {
+		du.logger.Info("Could not determine license of %s@%s: %v", dep.Name, dep.LatestVersion, err)
+		return
+	}
+
+	license := &models.LicenseChange{From: detectLicense(oldDir), To: detectLicense(newDir)}
+	analysis.License = license
+	if license.From == license.To {
+		return
+	}
+
+	du.logger.Warn("License of %s changed from %s to %s", dep.Name, LicenseName(license.From), LicenseName(license.To))
+	allowed := du.cfg.ForModule(dep.Name).Licenses
+	if allowed.Allows(license.To) {
+		return
+	}
+
+	reason := "License changed from " + LicenseName(license.From) + " to " + LicenseName(license.To) +
+		", which is not in the allowed licenses"
+	if !analysis.ShouldUpdate && analysis.RejectionReason != "" {
+		reason += "; " + analysis.RejectionReason
+	}
+	analysis.ShouldUpdate = false
+	analysis.RejectionReason = reason
+}
+
+// LicenseName returns a printable license expression
+func LicenseName(expression string) string {
+	if expression == "" {
+		return "unknown"
+	}
+	return expression
+}
+
+// detectLicense returns the SPDX expression of the license of a module
+// directory, or an empty string when it cannot be identified. Several
+// license files are treated as a choice between the licenses.
+func detectLicense(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+
+	ids := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.IsDir() || !isLicenseFile(entry.Name()) {
+			continue
+		}
+		if id := identifyLicense(readFile(dir, entry.Name())); id != "" {
+			ids[id] = true
+		}
+	}
+
+	// Without license files fall back to SPDX headers in the sources
+	if len(ids) == 0 {
+		collectSPDXHeaders(dir, entries, ids)
+	}
+
+	expressions := make([]string, 0, len(ids))
+	for id := range ids {
+		expressions = append(expressions, id)
+	}
+	sort.Strings(expressions)
+
+	return strings.Join(expressions, " OR ")
+}
+
+// collectSPDXHeaders adds the licenses tagged in the headers of the Go files of a directory
+func collectSPDXHeaders(dir string, entries []os.DirEntry, ids map[string]bool) {
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+		if match := spdxHeader.FindStringSubmatch(readFile(dir, entry.Name())); match != nil {
+			ids[strings.TrimSpace(match[1])] = true
+		}
+	}
+}
+
+// readFile returns the content of a file, or an empty string when it cannot be read
+func readFile(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// isLicenseFile reports whether a file name is that of a license file
+func isLicenseFile(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, prefix := range licenseFilePrefixes {
+		if strings.HasPrefix(upper, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// identifyLicense returns the SPDX identifier of a license text, preferring an
+// explicit SPDX tag over the title and the text signatures
+func identifyLicense(text string) string {
+	if match := spdxHeader.FindStringSubmatch(text); match != nil {
+		return strings.TrimSpace(match[1])
+	}
+
+	normalized := normalizeLicense(text)
+	if id := identifyTitle(normalized); id != "" {
+		return id
+	}
+	for i := range licenseSignatures {
+		if licenseSignatures[i].matches(normalized) {
+			return licenseSignatures[i].id
+		}
+	}
+
+	return ""
+}
+
+// identifyTitle returns the license whose title comes first in the opening of
+// a normalized text, or an empty string when none does
+func identifyTitle(text string) string {
+	opening := text[:min(len(text), titleWindow)]
+
+	id, first := "", len(opening)
+	for _, title := range licenseTitles {
+		if i := strings.Index(opening, title.title); i >= 0 && i < first {
+			id, first = title.id, i
+		}
+	}
+	return id
+}
+
+// normalizeLicense lower-cases a license text and reduces everything but
+// letters, digits and dots to single spaces
+func normalizeLicense(text string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.'
+	}), " ")
+}
+
+// matches reports whether a normalized license text carries the signature
+func (s *licenseSignature) matches(text string) bool {
+	for _, phrase := range s.phrases {
+		if !strings.Contains(text, phrase) {
+			return false
+		}
+	}
+	return true
+}
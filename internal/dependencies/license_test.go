@@ -0,0 +1,47 @@
+package dependencies
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIdentifyLicense(t *testing.T) {
+	// The texts are verbatim, but for AGPL-3.0 which holds the opening of the
+	// license up to its mention of the GNU General Public License
+	tests := []struct {
+		file string
+		want string
+	}{
+		{file: "MIT.txt", want: "MIT"},
+		{file: "BSD-3-Clause.txt", want: "BSD-3-Clause"},
+		{file: "Apache-2.0.txt", want: "Apache-2.0"},
+		{file: "MPL-2.0.txt", want: "MPL-2.0"},
+		{file: "MPL-2.0-copyright.txt", want: "MPL-2.0"},
+		{file: "GPL-2.0.txt", want: "GPL-2.0"},
+		{file: "GPL-3.0.txt", want: "GPL-3.0"},
+		{file: "LGPL-2.0.txt", want: "LGPL-2.0"},
+		{file: "LGPL-2.1.txt", want: "LGPL-2.1"},
+		{file: "LGPL-3.0.txt", want: "LGPL-3.0"},
+		{file: "AGPL-3.0.txt", want: "AGPL-3.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			text, err := os.ReadFile(filepath.Join("testdata", "licenses", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := identifyLicense(string(text)); got != tt.want {
+				t.Errorf("identifyLicense() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdentifyLicenseTag(t *testing.T) {
+	text := "// SPDX-License-Identifier: Apache-2.0 OR MIT\n\nPermission is hereby granted, free of charge"
+	if got := identifyLicense(text); got != "Apache-2.0 OR MIT" {
+		t.Errorf("identifyLicense() = %q, want the SPDX tag", got)
+	}
+}
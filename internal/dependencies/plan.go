@@ -0,0 +1,125 @@
+package dependencies
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// PlanVersion is the version of the plan file format
+const PlanVersion = 1
+
+// PlanChange is a single update of a plan with the analysis that approved it
+type PlanChange struct {
+	Module   string                 `json:"module"`
+	From     string                 `json:"from"`
+	To       string                 `json:"to"`
+	Analysis *models.UpdateAnalysis `json:"analysis,omitempty"`
+}
+
+// Plan is a list of updates decided on now and applied later, typically after review
+type Plan struct {
+	Version   int          `json:"version"`
+	CreatedAt time.Time    `json:"created_at"`
+	GoModHash string       `json:"go_mod_hash"` // sha256 of go.mod when the plan was made
+	Changes   []PlanChange `json:"changes"`
+}
+
+// NewPlan creates a plan applying the given updates to the project as it is now
+func (du *DependencyUpdater) NewPlan(analyses []*models.UpdateAnalysis) (*Plan, error) {
+	hashes, err := hashModuleFiles(du.projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{
+		Version:   PlanVersion,
+		CreatedAt: time.Now(),
+		GoModHash: hashes["go.mod"],
+		Changes:   make([]PlanChange, 0, len(analyses)),
+	}
+
+	for _, analysis := range analyses {
+		plan.Changes = append(plan.Changes, PlanChange{
+			Module:   analysis.Dependency.Name,
+			From:     analysis.Dependency.CurrentVersion,
+			To:       analysis.Dependency.LatestVersion,
+			Analysis: analysis,
+		})
+	}
+
+	return plan, nil
+}
+
+// WritePlan saves a plan to a file
+func WritePlan(filename string, plan *Plan) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode plan: %w", err)
+	}
+
+	if err := os.WriteFile(filename, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+
+	return nil
+}
+
+// ReadPlan loads a plan saved by WritePlan
+func ReadPlan(filename string) (*Plan, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	var plan Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to decode plan %s: %w", filename, err)
+	}
+
+	if plan.Version != PlanVersion {
+		return nil, fmt.Errorf("unsupported plan version %d in %s", plan.Version, filename)
+	}
+
+	return &plan, nil
+}
+
+// Updates returns the analyses of the changes of a plan, linked to their dependencies
+func (p *Plan) Updates() []*models.UpdateAnalysis {
+	analyses := make([]*models.UpdateAnalysis, 0, len(p.Changes))
+	for i := range p.Changes {
+		change := &p.Changes[i]
+
+		analysis := change.Analysis
+		if analysis == nil {
+			analysis = &models.UpdateAnalysis{ShouldUpdate: true}
+		}
+		analysis.Dependency = &models.Dependency{
+			Name:           change.Module,
+			CurrentVersion: change.From,
+			LatestVersion:  change.To,
+			UpdateNeeded:   true,
+		}
+
+		analyses = append(analyses, analysis)
+	}
+	return analyses
+}
+
+// CheckPlan refuses a plan made for a different go.mod than the project's current one
+func (du *DependencyUpdater) CheckPlan(plan *Plan) error {
+	hashes, err := hashModuleFiles(du.projectPath)
+	if err != nil {
+		return err
+	}
+
+	if hashes["go.mod"] != plan.GoModHash {
+		return fmt.Errorf("go.mod has changed since the plan was made on %s, refusing to apply it",
+			plan.CreatedAt.Format(time.RFC1123))
+	}
+
+	return nil
+}
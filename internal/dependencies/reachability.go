@@ -0,0 +1,220 @@
+package dependencies
+
+import (
+	"fmt"
+	"go/types"
+	"sync"
+
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/callgraph/cha"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// projectReachability holds the packages imported by the project and the
+// functions reachable from its own code
+type projectReachability struct {
+	imported  map[string]bool // package path -> imported directly or transitively
+	reachable map[string]bool // "pkg/path.Symbol" or "pkg/path.Type.Method" -> reachable
+	packages  map[string]bool // package path -> any function reachable
+}
+
+// reachabilityAnalysis computes the project reachability once, on first use
+type reachabilityAnalysis struct {
+	once   sync.Once
+	result *projectReachability
+	err    error
+}
+
+// markReachability determines for each vulnerability whether the project calls the vulnerable code
+func (du *DependencyUpdater) markReachability(analysis *models.UpdateAnalysis) {
+	if len(analysis.Vulnerabilities) == 0 {
+		return
+	}
+
+	du.reachability.once.Do(func() {
+		du.logger.Print("🕸️  Building call graph to check vulnerability reachability...")
+		du.reachability.result, du.reachability.err = analyzeReachability(du.projectPath)
+	})
+
+	if du.reachability.err != nil {
+		du.logger.Warn("Could not analyze reachability: %v", du.reachability.err)
+		return
+	}
+
+	for i := range analysis.Vulnerabilities {
+		vuln := &analysis.Vulnerabilities[i]
+		vuln.Reachability = du.reachability.result.classify(vuln)
+	}
+}
+
+// analyzeReachability loads the project, builds its call graph with class
+// hierarchy analysis and collects everything reachable from the project's own functions
+func analyzeReachability(projectPath string) (result *projectReachability, err error) {
+	// The SSA builder panics on syntax it does not support yet
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("failed to build call graph: %v", r)
+		}
+	}()
+
+	initial, err := loadProject(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result = &projectReachability{
+		imported:  make(map[string]bool),
+		reachable: make(map[string]bool),
+		packages:  make(map[string]bool),
+	}
+
+	packages.Visit(initial, nil, func(pkg *packages.Package) {
+		result.imported[pkg.PkgPath] = true
+	})
+
+	prog, ssaPkgs := ssautil.AllPackages(initial, ssa.InstantiateGenerics)
+	// Build packages one by one so that builder panics surface in this goroutine
+	for _, pkg := range prog.AllPackages() {
+		pkg.Build()
+	}
+
+	roots := make(map[*ssa.Package]bool, len(ssaPkgs))
+	for _, pkg := range ssaPkgs {
+		if pkg != nil {
+			roots[pkg] = true
+		}
+	}
+
+	graph := cha.CallGraph(prog)
+	graph.DeleteSyntheticNodes()
+	result.walk(graph, roots)
+
+	return result, nil
+}
+
+// loadProject loads the project packages with their dependencies and full syntax
+func loadProject(projectPath string) ([]*packages.Package, error) {
+	cfg := &packages.Config{
+		Mode: packages.LoadAllSyntax,
+		Dir:  projectPath,
+	}
+
+	initial, err := packages.Load(cfg, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+
+	var loadErr error
+	packages.Visit(initial, nil, func(pkg *packages.Package) {
+		if len(pkg.Errors) > 0 && loadErr == nil {
+			loadErr = pkg.Errors[0]
+		}
+	})
+	if loadErr != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", loadErr)
+	}
+
+	return initial, nil
+}
+
+// walk records every function reachable from the functions of the root packages
+func (r *projectReachability) walk(graph *callgraph.Graph, roots map[*ssa.Package]bool) {
+	var queue []*callgraph.Node
+	visited := make(map[*callgraph.Node]bool)
+	for fn, node := range graph.Nodes {
+		if fn != nil && roots[fn.Pkg] {
+			queue = append(queue, node)
+			visited[node] = true
+		}
+	}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		r.record(node.Func)
+
+		for _, edge := range node.Out {
+			if !visited[edge.Callee] {
+				visited[edge.Callee] = true
+				queue = append(queue, edge.Callee)
+			}
+		}
+	}
+}
+
+// record marks a function as reachable
+func (r *projectReachability) record(fn *ssa.Function) {
+	if fn == nil || fn.Pkg == nil {
+		return
+	}
+
+	pkgPath := fn.Pkg.Pkg.Path()
+	r.packages[pkgPath] = true
+	r.reachable[pkgPath+"."+symbolName(fn)] = true
+}
+
+// classify returns how exposed the project is to a vulnerability
+func (r *projectReachability) classify(vuln *models.Vulnerability) models.Reachability {
+	// Without the affected packages nothing rules the vulnerability out
+	if len(vuln.Packages) == 0 {
+		return models.ReachabilityUnknown
+	}
+
+	reachability := models.ReachabilityNotImported
+
+	for _, pkg := range vuln.Packages {
+		if !r.imported[pkg.Path] {
+			continue
+		}
+		reachability = models.ReachabilityImported
+
+		if len(pkg.Symbols) == 0 && r.packages[pkg.Path] {
+			return models.ReachabilityCalled
+		}
+		for _, symbol := range pkg.Symbols {
+			if r.reachable[pkg.Path+"."+symbol] {
+				return models.ReachabilityCalled
+			}
+		}
+	}
+
+	return reachability
+}
+
+// symbolName returns the vulndb symbol name of a function: Func or Type.Method
+func symbolName(fn *ssa.Function) string {
+	if fn.Origin() != nil {
+		fn = fn.Origin()
+	}
+
+	recv := fn.Signature.Recv()
+	if recv == nil {
+		return fn.Name()
+	}
+
+	typ := recv.Type()
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	if named, ok := typ.(*types.Named); ok {
+		return named.Obj().Name() + "." + fn.Name()
+	}
+
+	return fn.Name()
+}
+
+// reachabilityPriority returns how much a vulnerability raises the update priority
+func reachabilityPriority(reachability models.Reachability) int {
+	switch reachability {
+	case models.ReachabilityCalled:
+		return 3
+	case models.ReachabilityImported, models.ReachabilityUnknown:
+		return 2
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,56 @@
+package dependencies
+
+import (
+	"testing"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+func TestClassify(t *testing.T) {
+	r := &projectReachability{
+		imported:  map[string]bool{"example.com/a": true, "example.com/b": true},
+		reachable: map[string]bool{"example.com/a.Parse": true},
+		packages:  map[string]bool{"example.com/a": true},
+	}
+
+	tests := []struct {
+		name     string
+		packages []models.VulnerablePackage
+		want     models.Reachability
+	}{
+		{name: "no package data", want: models.ReachabilityUnknown},
+		{
+			name:     "not imported",
+			packages: []models.VulnerablePackage{{Path: "example.com/c"}},
+			want:     models.ReachabilityNotImported,
+		},
+		{
+			name:     "imported only",
+			packages: []models.VulnerablePackage{{Path: "example.com/b"}},
+			want:     models.ReachabilityImported,
+		},
+		{
+			name:     "symbol not called",
+			packages: []models.VulnerablePackage{{Path: "example.com/a", Symbols: []string{"Format"}}},
+			want:     models.ReachabilityImported,
+		},
+		{
+			name:     "symbol called",
+			packages: []models.VulnerablePackage{{Path: "example.com/a", Symbols: []string{"Format", "Parse"}}},
+			want:     models.ReachabilityCalled,
+		},
+		{
+			name:     "whole package called",
+			packages: []models.VulnerablePackage{{Path: "example.com/a"}},
+			want:     models.ReachabilityCalled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.classify(&models.Vulnerability{Packages: tt.packages}); got != tt.want {
+				t.Errorf("classify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package dependencies
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// Risk factor names
+const (
+	factorSemver          = "semver"
+	factorBreakingChanges = "breaking-changes"
+	factorCommitVolume    = "commit-volume"
+	factorReleaseAge      = "release-age"
+	factorAPIDiff         = "api-diff"
+)
+
+// assessRisk gathers the risk signals of an update and scores them
+func (du *DependencyUpdater) assessRisk(analysis *models.UpdateAnalysis) {
+	dep := analysis.Dependency
+	analysis.Risk = &models.RiskAssessment{}
+
+	addRiskFactor(analysis, semverFactor(dep.CurrentVersion, dep.LatestVersion))
+
+	count, score := du.analyzer.RejectScore(analysis)
+	addRiskFactor(analysis, models.RiskFactor{
+		Name:   factorBreakingChanges,
+		Points: min(score*10, 40),
+		Detail: fmt.Sprintf("%d breaking changes (weighted score %d)", count, score),
+	})
+
+	addRiskFactor(analysis, commitVolumeFactor(len(analysis.Commits)))
+
+	if factor, ok := vulnerabilityFactor(analysis.Vulnerabilities); ok {
+		addRiskFactor(analysis, factor)
+	}
+
+	if diff, err := du.diffModuleGraph(dep); err != nil {
+		du.logger.Info("Could not resolve module graph after updating %s: %v", dep.Name, err)
+	} else {
+		analysis.ModuleGraph = diff
+		addRiskFactor(analysis, graphGrowthFactor(diff, du.cfg.Risk.GraphGrowth))
+	}
+
+	du.measureSizeImpact(analysis)
+	if analysis.SizeImpact != nil {
+		addRiskFactor(analysis, binarySizeFactor(analysis.SizeImpact, du.cfg.Size.MaxGrowth))
+	}
+
+	if factor, ok := goVersionFactor(analysis.GoRequirement, du.projectGoVersion()); ok {
+		addRiskFactor(analysis, factor)
+	}
+
+	if times, err := du.fetcher.GetVersionTimes(dep.Name, dep.LatestVersion); err != nil {
+		du.logger.Info("Could not determine release time of %s@%s: %v", dep.Name, dep.LatestVersion, err)
+	} else if released, ok := times[dep.LatestVersion]; ok && !released.IsZero() {
+		addRiskFactor(analysis, releaseAgeFactor(time.Since(released)))
+	}
+
+	if diff, err := du.diffAPI(dep); err != nil {
+		du.logger.Info("Could not compare API of %s: %v", dep.Name, err)
+	} else {
+		analysis.APIDiff = diff
+		addRiskFactor(analysis, models.RiskFactor{
+			Name:   factorAPIDiff,
+			Points: min(len(diff.Incompatible)*5, 30),
+			Detail: fmt.Sprintf("%d incompatible, %d compatible API changes", len(diff.Incompatible), len(diff.Compatible)),
+		})
+	}
+}
+
+// decideOnRisk levels the risk score and rejects updates above the approval threshold
+func (du *DependencyUpdater) decideOnRisk(analysis *models.UpdateAnalysis) {
+	risk := analysis.Risk
+	risk.Score = max(risk.Score, 0)
+	risk.Level = du.cfg.Risk.Level(risk.Score)
+
+	policy := du.cfg.ForModule(analysis.Dependency.Name).Risk
+	if analysis.ShouldUpdate && !policy.Approves(risk.Level) {
+		analysis.ShouldUpdate = false
+		analysis.RejectionReason = fmt.Sprintf("Risk score %d (%s) exceeds the maximum approved level %s",
+			risk.Score, risk.Level, policy.MaxLevel)
+	}
+}
+
+// RejectedAsRisky reports whether an update was rejected for its risk level or
+// its breaking changes, rather than for lacking improvements or by a policy veto
+func (du *DependencyUpdater) RejectedAsRisky(analysis *models.UpdateAnalysis) bool {
+	if analysis == nil || analysis.ShouldUpdate {
+		return false
+	}
+
+	policy := du.cfg.ForModule(analysis.Dependency.Name).Risk
+	if analysis.Risk != nil && !policy.Approves(analysis.Risk.Level) {
+		return true
+	}
+
+	count, _ := du.analyzer.RejectScore(analysis)
+	return count > 0
+}
+
+// diffAPI downloads both versions of a dependency and compares their exported API
+func (du *DependencyUpdater) diffAPI(dep *models.Dependency) (*models.APIDiff, error) {
+	oldDir, err := du.fetcher.DownloadModule(dep.Name, dep.CurrentVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	newDir, err := du.fetcher.DownloadModule(dep.Name, dep.LatestVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffModuleAPI(oldDir, newDir)
+}
+
+// addRiskFactor records a risk factor and adds its points to the score
+func addRiskFactor(analysis *models.UpdateAnalysis, factor models.RiskFactor) {
+	analysis.Risk.Factors = append(analysis.Risk.Factors, factor)
+	analysis.Risk.Score += factor.Points
+}
+
+// BumpKind returns the size of the version change: major, minor, patch or prerelease
+func BumpKind(from, to string) string {
+	switch {
+	case semver.Major(from) != semver.Major(to):
+		return "major"
+	case semver.MajorMinor(from) != semver.MajorMinor(to):
+		return "minor"
+	case semver.Canonical(from) != semver.Canonical(to) && semver.Prerelease(from)+semver.Prerelease(to) == "":
+		return "patch"
+	default:
+		return "prerelease"
+	}
+}
+
+// semverFactor scores the size of the version change
+func semverFactor(from, to string) models.RiskFactor {
+	kind := BumpKind(from, to)
+	factor := models.RiskFactor{Name: factorSemver, Detail: kind + " version bump"}
+
+	switch {
+	case kind == "major":
+		factor.Points = 30
+	case kind == "minor" && semver.Major(from) == "v0":
+		// v0 minor versions carry no compatibility promise
+		factor.Points = 20
+		factor.Detail = "v0 minor version bump"
+	case kind == "minor":
+		factor.Points = 10
+	}
+
+	if semver.Prerelease(to) != "" {
+		factor.Points += 15
+		factor.Detail += " to a prerelease"
+	}
+
+	return factor
+}
+
+// commitVolumeFactor scores the number of commits between versions
+func commitVolumeFactor(commits int) models.RiskFactor {
+	factor := models.RiskFactor{Name: factorCommitVolume, Detail: fmt.Sprintf("%d commits", commits)}
+
+	switch {
+	case commits >= 100:
+		factor.Points = 15
+	case commits >= 30:
+		factor.Points = 10
+	case commits >= 10:
+		factor.Points = 5
+	}
+
+	return factor
+}
+
+// releaseAgeFactor scores how recently the target version was published
+func releaseAgeFactor(age time.Duration) models.RiskFactor {
+	days := int(age.Hours() / 24)
+	factor := models.RiskFactor{Name: factorReleaseAge, Detail: fmt.Sprintf("released %d days ago", days)}
+
+	switch {
+	case days < 7:
+		factor.Points = 15
+	case days < 30:
+		factor.Points = 5
+	}
+
+	return factor
+}
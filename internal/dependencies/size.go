@@ -0,0 +1,165 @@
+package dependencies
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// factorBinarySize is the risk factor of binary size growth
+const factorBinarySize = "binary-size"
+
+// buildWeight is the total size and the number of linked packages of the main packages
+type buildWeight struct {
+	size     int64
+	packages int
+}
+
+// sizeBaseline measures the project's binaries once, before any update
+type sizeBaseline struct {
+	once   sync.Once
+	weight buildWeight
+	err    error
+}
+
+// measureSizeImpact builds the project's main packages with the current and the
+// target version of a dependency and records the difference
+func (du *DependencyUpdater) measureSizeImpact(analysis *models.UpdateAnalysis) {
+	if !du.cfg.Size.Enabled {
+		return
+	}
+
+	dep := analysis.Dependency
+
+	du.sizeBaseline.once.Do(func() {
+		du.logger.Print("📏 Building main packages to measure binary size...")
+		du.sizeBaseline.weight, du.sizeBaseline.err = du.measureBuild(nil)
+	})
+	if du.sizeBaseline.err != nil {
+		du.logger.Warn("Could not measure binary size: %v", du.sizeBaseline.err)
+		return
+	}
+
+	after, err := du.measureBuild(dep)
+	if err != nil {
+		du.logger.Warn("Could not measure binary size with %s@%s: %v", dep.Name, dep.LatestVersion, err)
+		return
+	}
+
+	before := du.sizeBaseline.weight
+	impact := &models.SizeImpact{
+		SizeBefore:     before.size,
+		SizeAfter:      after.size,
+		PackagesBefore: before.packages,
+		PackagesAfter:  after.packages,
+	}
+	analysis.SizeImpact = impact
+
+	if sizeGrowth(impact) > float64(du.cfg.Size.MaxGrowth) {
+		du.logger.Warn("Updating %s grows the binaries by %s", dep.Name, FormatSizeImpact(impact))
+	}
+}
+
+// measureBuild builds the main packages against a scratch copy of the module
+// files, with a dependency updated when one is given
+func (du *DependencyUpdater) measureBuild(dep *models.Dependency) (buildWeight, error) {
+	dir, err := os.MkdirTemp("", "gupdeps-size-")
+	if err != nil {
+		return buildWeight{}, fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := copyModuleFiles(du.projectPath, dir); err != nil {
+		return buildWeight{}, err
+	}
+	modFile := "-modfile=" + filepath.Join(dir, "go.mod")
+
+	if dep != nil {
+		if output, err := runGo(du.projectPath, "get", modFile, dep.Name+"@"+dep.LatestVersion); err != nil {
+			return buildWeight{}, fmt.Errorf("go get failed: %w\nOutput: %s", err, output)
+		}
+	}
+
+	mains, err := listLines(du.projectPath, "list", modFile, "-f", `{{if eq .Name "main"}}{{.ImportPath}}{{end}}`, "./...")
+	if err != nil {
+		return buildWeight{}, err
+	}
+	if len(mains) == 0 {
+		return buildWeight{}, fmt.Errorf("no main packages in the project")
+	}
+
+	deps, err := listLines(du.projectPath, append([]string{"list", modFile, "-deps"}, mains...)...)
+	if err != nil {
+		return buildWeight{}, err
+	}
+
+	size, err := du.buildSize(dir, modFile, mains)
+	if err != nil {
+		return buildWeight{}, err
+	}
+
+	return buildWeight{size: size, packages: len(deps)}, nil
+}
+
+// buildSize builds main packages into a directory and returns their total size
+func (du *DependencyUpdater) buildSize(dir, modFile string, mains []string) (int64, error) {
+	var size int64
+	for i, pkg := range mains {
+		binary := filepath.Join(dir, fmt.Sprintf("main%d", i))
+		if output, err := runGo(du.projectPath, "build", modFile, "-o", binary, pkg); err != nil {
+			return 0, fmt.Errorf("failed to build %s: %w\nOutput: %s", pkg, err, output)
+		}
+
+		info, err := os.Stat(binary)
+		if err != nil {
+			return 0, fmt.Errorf("failed to measure %s: %w", pkg, err)
+		}
+		size += info.Size()
+	}
+
+	return size, nil
+}
+
+// listLines runs a go list command and returns its non-empty output lines
+func listLines(dir string, args ...string) ([]string, error) {
+	output, err := runGo(dir, args...)
+	if err != nil {
+		return nil, fmt.Errorf("go %s failed: %w\nOutput: %s", args[0], err, output)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines, nil
+}
+
+// binarySizeFactor scores binary growth above the configured percentage
+func binarySizeFactor(impact *models.SizeImpact, maxGrowth int) models.RiskFactor {
+	factor := models.RiskFactor{Name: factorBinarySize, Detail: FormatSizeImpact(impact)}
+	if sizeGrowth(impact) > float64(maxGrowth) {
+		factor.Points = 15
+	}
+	return factor
+}
+
+// sizeGrowth returns the binary growth in percent
+func sizeGrowth(impact *models.SizeImpact) float64 {
+	if impact.SizeBefore == 0 {
+		return 0
+	}
+	return float64(impact.SizeAfter-impact.SizeBefore) * 100 / float64(impact.SizeBefore)
+}
+
+// FormatSizeImpact describes the size and linked package changes of an update
+func FormatSizeImpact(impact *models.SizeImpact) string {
+	return fmt.Sprintf("%+d KiB (%+.1f%%), %+d linked packages",
+		(impact.SizeAfter-impact.SizeBefore)/1024, sizeGrowth(impact), impact.PackagesAfter-impact.PackagesBefore)
+}
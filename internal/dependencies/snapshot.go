@@ -0,0 +1,278 @@
+package dependencies
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// journalPath is the location of the undo journal relative to the project root
+var journalPath = filepath.Join(".gupdeps", "journal.json")
+
+// snapshotFiles lists the module files captured before a session, relative to the project root
+var snapshotFiles = []string{
+	"go.mod",
+	"go.sum",
+	filepath.Join("vendor", "modules.txt"),
+}
+
+// SnapshotFile holds the captured state of a single module file
+type SnapshotFile struct {
+	Path    string `json:"path"`
+	Exists  bool   `json:"exists"`
+	Content []byte `json:"content,omitempty"`
+}
+
+// Snapshot holds the module files of a project at a point in time
+type Snapshot struct {
+	CreatedAt time.Time      `json:"created_at"`
+	Files     []SnapshotFile `json:"files"`
+}
+
+// JournalEntry records a single update applied during a session
+type JournalEntry struct {
+	Module string `json:"module"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+// Journal records the last applied session so it can be undone
+type Journal struct {
+	CreatedAt time.Time         `json:"created_at"`
+	Updates   []JournalEntry    `json:"updates"`
+	Before    *Snapshot         `json:"before"`
+	After     map[string]string `json:"after"` // file path -> sha256 of content after the session
+}
+
+// takeSnapshot captures the current module files of the project
+func takeSnapshot(projectPath string) (*Snapshot, error) {
+	snapshot := &Snapshot{CreatedAt: time.Now()}
+
+	for _, name := range snapshotFiles {
+		content, err := os.ReadFile(filepath.Join(projectPath, name))
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			snapshot.Files = append(snapshot.Files, SnapshotFile{Path: name})
+		case err != nil:
+			return nil, fmt.Errorf("failed to snapshot %s: %w", name, err)
+		default:
+			snapshot.Files = append(snapshot.Files, SnapshotFile{Path: name, Exists: true, Content: content})
+		}
+	}
+
+	return snapshot, nil
+}
+
+// restore writes the captured module files back to the project
+func (s *Snapshot) restore(projectPath string) error {
+	for _, file := range s.Files {
+		path := filepath.Join(projectPath, file.Path)
+
+		if !file.Exists {
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to remove %s: %w", file.Path, err)
+			}
+			continue
+		}
+
+		if err := os.WriteFile(path, file.Content, 0o644); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", file.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// hashModuleFiles returns the sha256 of each module file, empty for missing files
+func hashModuleFiles(projectPath string) (map[string]string, error) {
+	hashes := make(map[string]string, len(snapshotFiles))
+
+	for _, name := range snapshotFiles {
+		content, err := os.ReadFile(filepath.Join(projectPath, name))
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			hashes[name] = ""
+		case err != nil:
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		default:
+			sum := sha256.Sum256(content)
+			hashes[name] = hex.EncodeToString(sum[:])
+		}
+	}
+
+	return hashes, nil
+}
+
+// Session applies a set of updates transactionally: any failure restores
+// the module files captured when the session began
+type Session struct {
+	updater  *DependencyUpdater
+	snapshot *Snapshot
+	applied  []JournalEntry
+}
+
+// BeginSession snapshots the module files and starts a new update session
+func (du *DependencyUpdater) BeginSession() (*Session, error) {
+	snapshot, err := takeSnapshot(du.projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Session{updater: du, snapshot: snapshot}, nil
+}
+
+// Apply applies an update within the session, rolling the session back on failure
+func (s *Session) Apply(dep *models.Dependency) error {
+	if err := s.updater.ApplyUpdate(dep); err != nil {
+		return s.fail(err)
+	}
+
+	s.applied = append(s.applied, JournalEntry{
+		Module: dep.Name,
+		From:   dep.CurrentVersion,
+		To:     dep.LatestVersion,
+	})
+	return nil
+}
+
+// Tidy runs go mod tidy within the session, rolling the session back on failure
+func (s *Session) Tidy() error {
+	if err := s.updater.RunModTidy(); err != nil {
+		return s.fail(err)
+	}
+	return nil
+}
+
+// Applied returns the number of updates applied so far
+func (s *Session) Applied() int {
+	return len(s.applied)
+}
+
+// Rollback restores the module files captured when the session began
+func (s *Session) Rollback() error {
+	if err := s.snapshot.restore(s.updater.projectPath); err != nil {
+		return fmt.Errorf("failed to roll back session: %w", err)
+	}
+
+	s.applied = nil
+	s.updater.logger.Warn("Rolled back go.mod/go.sum to the state before the session")
+	return nil
+}
+
+// Commit finishes the session and saves a journal so it can be undone later
+func (s *Session) Commit() error {
+	if len(s.applied) == 0 {
+		return nil
+	}
+
+	after, err := hashModuleFiles(s.updater.projectPath)
+	if err != nil {
+		return err
+	}
+
+	journal := &Journal{
+		CreatedAt: time.Now(),
+		Updates:   s.applied,
+		Before:    s.snapshot,
+		After:     after,
+	}
+
+	return writeJournal(s.updater.projectPath, journal)
+}
+
+// fail rolls the session back and returns the original error
+func (s *Session) fail(cause error) error {
+	if err := s.Rollback(); err != nil {
+		return fmt.Errorf("%w (rollback failed: %w)", cause, err)
+	}
+	return cause
+}
+
+// writeJournal saves the journal of the last applied session
+func writeJournal(projectPath string, journal *Journal) error {
+	path := filepath.Join(projectPath, journalPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(journal, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode journal: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+
+	return nil
+}
+
+// Undo reverts the last applied session using the saved journal
+func (du *DependencyUpdater) Undo() (*Journal, error) {
+	path := filepath.Join(du.projectPath, journalPath)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errors.New("no applied session to undo")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	var journal Journal
+	if err := json.Unmarshal(data, &journal); err != nil {
+		return nil, fmt.Errorf("failed to decode journal: %w", err)
+	}
+	if err := journal.validate(); err != nil {
+		return nil, fmt.Errorf("invalid journal: %w", err)
+	}
+
+	current, err := hashModuleFiles(du.projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, hash := range journal.After {
+		if current[name] != hash {
+			return nil, fmt.Errorf("%s has changed since the last session, refusing to undo", name)
+		}
+	}
+
+	if err := journal.Before.restore(du.projectPath); err != nil {
+		return nil, err
+	}
+
+	if err := os.Remove(path); err != nil {
+		return nil, fmt.Errorf("failed to remove journal: %w", err)
+	}
+
+	return &journal, nil
+}
+
+// validate checks that a journal read back from disk restores and checks
+// exactly the module files, so a damaged journal cannot write elsewhere
+func (j *Journal) validate() error {
+	if j.Before == nil || len(j.Before.Files) == 0 {
+		return errors.New("no snapshot of the module files")
+	}
+	for _, file := range j.Before.Files {
+		if !slices.Contains(snapshotFiles, file.Path) {
+			return fmt.Errorf("%q is not a module file", file.Path)
+		}
+	}
+
+	for _, name := range snapshotFiles {
+		if _, ok := j.After[name]; !ok {
+			return fmt.Errorf("no hash of %s after the session", name)
+		}
+	}
+	return nil
+}
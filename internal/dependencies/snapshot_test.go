@@ -0,0 +1,90 @@
+package dependencies
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUndo(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "go.mod", "module example.com/before\n")
+
+	before, err := takeSnapshot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeTestFile(t, dir, "go.mod", "module example.com/after\n")
+	writeTestFile(t, dir, "go.sum", "example.com/dep v1.0.0 h1:abc=\n")
+	after, err := hashModuleFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeJournal(dir, &Journal{Before: before, After: after}); err != nil {
+		t.Fatal(err)
+	}
+
+	du := &DependencyUpdater{projectPath: dir}
+	if _, err := du.Undo(); err != nil {
+		t.Fatal(err)
+	}
+
+	if content := readFile(dir, "go.mod"); content != "module example.com/before\n" {
+		t.Errorf("go.mod = %q, want the content before the session", content)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "go.sum")); !os.IsNotExist(err) {
+		t.Error("go.sum did not exist before the session and must be removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, journalPath)); !os.IsNotExist(err) {
+		t.Error("the journal must be removed once undone")
+	}
+}
+
+func TestUndoInvalidJournal(t *testing.T) {
+	hashes := `"go.mod": "", "go.sum": "", "vendor/modules.txt": ""`
+	tests := []struct {
+		name    string
+		journal string
+		want    string
+	}{
+		{name: "empty", journal: `{}`, want: "no snapshot"},
+		{name: "null snapshot", journal: `{"before": null, "after": {` + hashes + `}}`, want: "no snapshot"},
+		{name: "no files", journal: `{"before": {"files": []}, "after": {` + hashes + `}}`, want: "no snapshot"},
+		{name: "empty hashes", journal: `{"before": {"files": [{"path": "go.mod"}]}, "after": {}}`, want: "no hash"},
+		{name: "missing hashes", journal: `{"before": {"files": [{"path": "go.mod"}]}, "after": {"go.mod": ""}}`, want: "no hash"},
+		{
+			name:    "foreign file",
+			journal: `{"before": {"files": [{"path": "../../etc/passwd", "exists": true}]}, "after": {` + hashes + `}}`,
+			want:    "not a module file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestFile(t, dir, journalPath, strings.ReplaceAll(tt.journal, "vendor/modules.txt", filepath.Join("vendor", "modules.txt")))
+
+			du := &DependencyUpdater{projectPath: dir}
+			_, err := du.Undo()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Undo() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// writeTestFile writes a file of a test project, creating its directory
+func writeTestFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
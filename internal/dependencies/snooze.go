@@ -0,0 +1,104 @@
+package dependencies
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// snoozePath is the location of the snoozed modules relative to the project root
+var snoozePath = filepath.Join(".gupdeps", "snooze.json")
+
+// snoozeList holds the modules whose updates are postponed, loaded once
+type snoozeList struct {
+	once  sync.Once
+	until map[string]time.Time // module -> end of the snooze
+	err   error
+}
+
+// Snooze postpones the updates of a module for the given number of days and
+// returns when the snooze ends
+func (du *DependencyUpdater) Snooze(module string, days int) (time.Time, error) {
+	snoozes, err := du.loadSnoozes()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	now := time.Now()
+	until := now.AddDate(0, 0, days)
+	for name, end := range snoozes {
+		if now.After(end) {
+			delete(snoozes, name)
+		}
+	}
+	snoozes[module] = until
+
+	path := filepath.Join(du.projectPath, snoozePath)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return time.Time{}, fmt.Errorf("failed to create snooze directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(snoozes, "", "  ")
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to encode snoozed modules: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return time.Time{}, fmt.Errorf("failed to write snoozed modules: %w", err)
+	}
+
+	return until, nil
+}
+
+// loadSnoozes reads the snoozed modules of the project, once
+func (du *DependencyUpdater) loadSnoozes() (map[string]time.Time, error) {
+	du.snoozes.once.Do(func() {
+		du.snoozes.until = make(map[string]time.Time)
+
+		data, err := os.ReadFile(filepath.Join(du.projectPath, snoozePath))
+		if errors.Is(err, os.ErrNotExist) {
+			return
+		}
+		if err != nil {
+			du.snoozes.err = fmt.Errorf("failed to read snoozed modules: %w", err)
+			return
+		}
+
+		if err := json.Unmarshal(data, &du.snoozes.until); err != nil {
+			du.snoozes.err = fmt.Errorf("failed to decode snoozed modules: %w", err)
+		}
+	})
+
+	return du.snoozes.until, du.snoozes.err
+}
+
+// applySnooze holds back every update of a snoozed module until the snooze ends
+func (du *DependencyUpdater) applySnooze(dep *models.Dependency) {
+	if !dep.UpdateNeeded {
+		return
+	}
+
+	snoozes, err := du.loadSnoozes()
+	if err != nil {
+		du.logger.Warn("Could not load snoozed modules: %v", err)
+		return
+	}
+
+	until, ok := snoozes[dep.Name]
+	if !ok || time.Now().After(until) {
+		return
+	}
+
+	dep.Ignored = &models.IgnoredVersion{
+		Version: dep.LatestVersion,
+		Reason:  "module snoozed until " + until.Format(time.DateOnly),
+	}
+	dep.LatestVersion = dep.CurrentVersion
+	dep.UpdateNeeded = false
+}
@@ -3,27 +3,40 @@ package dependencies
 import (
 	"fmt"
 	"os/exec"
+	"strings"
+	"sync"
+	"time"
 
+	"golang.org/x/mod/semver"
+
+	"github.com/moeryomenko/gupdeps/internal/config"
 	"github.com/moeryomenko/gupdeps/internal/models"
 	"github.com/moeryomenko/gupdeps/internal/utils"
+	"github.com/moeryomenko/gupdeps/internal/vulndb"
 )
 
 // DependencyUpdater coordinates the dependency update process
 type DependencyUpdater struct {
-	projectPath string
-	fetcher     *DependencyFetcher
-	gitOps      *GitOperations
-	analyzer    *CommitAnalyzer
-	logger      *utils.Logger
+	projectPath  string
+	cfg          *config.Config
+	fetcher      *DependencyFetcher
+	gitOps       *GitOperations
+	analyzer     *CommitAnalyzer
+	vulnDB       *vulndb.DB
+	reachability reachabilityAnalysis
+	sizeBaseline sizeBaseline
+	snoozes      snoozeList
+	logger       *utils.Logger
 }
 
 // NewDependencyUpdater creates a new dependency updater
-func NewDependencyUpdater(projectPath string, logger *utils.Logger) *DependencyUpdater {
+func NewDependencyUpdater(projectPath string, cfg *config.Config, logger *utils.Logger) *DependencyUpdater {
 	return &DependencyUpdater{
 		projectPath: projectPath,
+		cfg:         cfg,
 		fetcher:     NewDependencyFetcher(projectPath, logger),
-		gitOps:      NewGitOperations(logger),
-		analyzer:    NewCommitAnalyzer(logger),
+		gitOps:      NewGitOperations(cfg.CacheDir, logger),
+		analyzer:    NewCommitAnalyzer(cfg.Rules, logger),
 		logger:      logger,
 	}
 }
@@ -54,33 +67,217 @@ func (du *DependencyUpdater) RunModTidy() error {
 	return nil
 }
 
+// FindUpdate resolves the newest version a dependency may be updated to,
+// honoring the ignore list, snoozed modules and the minimum release age
+func (du *DependencyUpdater) FindUpdate(dep *models.Dependency) error {
+	if err := du.fetcher.GetLatestVersion(dep); err != nil {
+		return fmt.Errorf("failed to get latest version: %w", err)
+	}
+	du.checkRetraction(dep)
+
+	// Skip ignored versions and snoozed modules, and hold back releases younger
+	// than the minimum release age
+	du.applyIgnoreList(dep)
+	du.applySnooze(dep)
+	if dep.Ignored != nil {
+		du.logger.Info("%s@%s is ignored: %s", dep.Name, dep.Ignored.Version, dep.Ignored.Reason)
+	}
+	du.applyCooldown(dep)
+	if dep.Pending != nil {
+		du.logger.Info("%s@%s is pending until %s", dep.Name, dep.Pending.Version, dep.Pending.Until.Format(time.DateOnly))
+	}
+
+	return nil
+}
+
 // AnalyzeDependency performs analysis on a single dependency
 func (du *DependencyUpdater) AnalyzeDependency(dep *models.Dependency) (*models.UpdateAnalysis, error) {
-	// Check if update is needed
-	if err := du.fetcher.GetLatestVersion(dep); err != nil {
-		return nil, fmt.Errorf("failed to get latest version: %w", err)
+	if err := du.FindUpdate(dep); err != nil {
+		return nil, err
 	}
 
 	if !dep.UpdateNeeded {
 		du.logger.Info("No update needed for %s (already at %s)", dep.Name, dep.CurrentVersion)
-		return &models.UpdateAnalysis{
+		analysis := &models.UpdateAnalysis{
 			Dependency:   dep,
 			ShouldUpdate: false,
-		}, nil
+		}
+		du.checkVulnerabilities(analysis)
+		du.markReachability(analysis)
+		return analysis, nil
 	}
 
-	// Get commits between versions
-	commits, err := du.gitOps.GetCommitsBetweenVersions(dep)
+	analysis, err := du.analyzeUpdate(dep)
+	if err != nil {
+		return nil, err
+	}
+
+	return du.preferFixVersion(analysis), nil
+}
+
+// AnalyzeTarget analyzes the update of a dependency to a given version, which
+// bypasses the ignore list and the minimum release age
+func (du *DependencyUpdater) AnalyzeTarget(dep *models.Dependency, version string) (*models.UpdateAnalysis, error) {
+	if !semver.IsValid(version) {
+		return nil, fmt.Errorf("invalid version %q", version)
+	}
+	if semver.Compare(version, dep.CurrentVersion) <= 0 {
+		return nil, fmt.Errorf("%s is not newer than the current version %s", version, dep.CurrentVersion)
+	}
+
+	if _, err := du.fetcher.GetVersionTimes(dep.Name, version); err != nil {
+		return nil, fmt.Errorf("unknown version %s@%s: %w", dep.Name, version, err)
+	}
+	du.checkRetraction(dep)
+
+	dep.LatestVersion = version
+	dep.UpdateNeeded = true
+
+	return du.analyzeUpdate(dep)
+}
+
+// analyzeUpdate analyzes the update of a dependency to its latest version
+func (du *DependencyUpdater) analyzeUpdate(dep *models.Dependency) (*models.UpdateAnalysis, error) {
+	// Get commits and release notes between versions
+	commits, tagNotes, err := du.gitOps.GetCommitsBetweenVersions(dep)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get commits: %w", err)
 	}
 
+	changelog := du.readChangelog(dep, tagNotes)
+
 	// Analyze the changes
-	analysis := du.analyzer.AnalyzeUpdate(dep, commits)
+	analysis := du.analyzer.AnalyzeUpdate(dep, commits, changelog)
+	du.checkVulnerabilities(analysis)
+	du.markReachability(analysis)
+	du.decideOnVulnerabilities(analysis)
+	prioritize(analysis)
+
+	// Score the risk of the update
+	du.checkGoRequirement(analysis)
+	du.assessRisk(analysis)
+	du.decideOnRisk(analysis)
+	du.decideOnGoRequirement(analysis)
+
+	// License changes veto any other decision
+	du.checkLicense(analysis)
+
 	return analysis, nil
 }
 
-// GetAllDependencies returns all direct dependencies
+// checkRetraction warns when the current version of a dependency is retracted
+func (du *DependencyUpdater) checkRetraction(dep *models.Dependency) {
+	if err := du.fetcher.GetRetraction(dep); err != nil {
+		du.logger.Info("Could not check retractions: %v", err)
+		return
+	}
+
+	if len(dep.Retracted) > 0 {
+		du.logger.Warn("%s@%s is retracted: %s", dep.Name, dep.CurrentVersion, strings.Join(dep.Retracted, "; "))
+	}
+}
+
+// KeepClones keeps the repository clones of the analyses until the returned
+// function is called, so showing the diff of a commit does not clone again.
+// Clones in the cache directory are kept regardless.
+func (du *DependencyUpdater) KeepClones() (release func()) {
+	du.gitOps.KeepClones()
+	return du.gitOps.Close
+}
+
+// GetCommitDiff returns the changes a commit of a dependency made to the module
+func (du *DependencyUpdater) GetCommitDiff(dep *models.Dependency, hash string) (string, error) {
+	return du.gitOps.GetCommitDiff(dep.Name, hash)
+}
+
+// GetDependency returns a direct dependency, regardless of the include and exclude patterns
+func (du *DependencyUpdater) GetDependency(module string) (*models.Dependency, error) {
+	deps, err := du.fetcher.GetDependencies()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dep := range deps {
+		if dep.Name == module {
+			return dep, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s is not a direct dependency of the project", module)
+}
+
+// GetAllDependencies returns the direct dependencies selected by the include and exclude patterns
 func (du *DependencyUpdater) GetAllDependencies() ([]*models.Dependency, error) {
-	return du.fetcher.GetDependencies()
+	deps, err := du.fetcher.GetDependencies()
+	if err != nil {
+		return nil, err
+	}
+
+	selected := deps[:0]
+	for _, dep := range deps {
+		if du.cfg.Selects(dep.Name) {
+			selected = append(selected, dep)
+		}
+	}
+
+	if len(selected) == 0 && du.cfg.Only != "" {
+		return nil, fmt.Errorf("%s is not a direct dependency of the project", du.cfg.Only)
+	}
+	if len(selected) == 0 && len(deps) > 0 {
+		du.logger.Warn("None of the %d direct dependencies matches the include and exclude patterns", len(deps))
+	}
+
+	return selected, nil
+}
+
+// AnalyzeDependencies analyzes dependencies with the configured concurrency.
+// Results and errors are returned in the order of the dependencies.
+func (du *DependencyUpdater) AnalyzeDependencies(deps []*models.Dependency) ([]*models.UpdateAnalysis, []error) {
+	analyses := make([]*models.UpdateAnalysis, len(deps))
+	errs := make([]error, len(deps))
+
+	du.AnalyzeEach(deps, func(i int, analysis *models.UpdateAnalysis, err error) {
+		analyses[i], errs[i] = analysis, err
+	})
+
+	return analyses, errs
+}
+
+// AnalyzeEach analyzes dependencies with the configured concurrency and calls
+// done with the index of every dependency as soon as its analysis completes.
+// done may be called concurrently.
+func (du *DependencyUpdater) AnalyzeEach(deps []*models.Dependency, done func(i int, analysis *models.UpdateAnalysis, err error)) {
+	du.parallel(deps, func(i int, dep *models.Dependency) {
+		analysis, err := du.AnalyzeDependency(dep)
+		done(i, analysis, err)
+	})
+}
+
+// FindUpdates resolves the update of each dependency with the configured
+// concurrency, returning the errors in the order of the dependencies
+func (du *DependencyUpdater) FindUpdates(deps []*models.Dependency) []error {
+	errs := make([]error, len(deps))
+
+	du.parallel(deps, func(i int, dep *models.Dependency) {
+		errs[i] = du.FindUpdate(dep)
+	})
+
+	return errs
+}
+
+// parallel calls fn for every dependency, running at most the configured number at once
+func (du *DependencyUpdater) parallel(deps []*models.Dependency, fn func(i int, dep *models.Dependency)) {
+	slots := make(chan struct{}, du.cfg.Concurrency)
+	var wg sync.WaitGroup
+	for i, dep := range deps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			fn(i, dep)
+		}()
+	}
+	wg.Wait()
 }
@@ -0,0 +1,212 @@
+package dependencies
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+	"github.com/moeryomenko/gupdeps/internal/vulndb"
+)
+
+// factorVulnerabilities is the risk factor of known advisories
+const factorVulnerabilities = "vulnerabilities"
+
+// SetVulnDB enables the vulnerability check against a local database
+func (du *DependencyUpdater) SetVulnDB(db *vulndb.DB) {
+	du.vulnDB = db
+}
+
+// checkVulnerabilities records the advisories affecting the current version of a dependency
+func (du *DependencyUpdater) checkVulnerabilities(analysis *models.UpdateAnalysis) {
+	if du.vulnDB == nil {
+		return
+	}
+
+	dep := analysis.Dependency
+	target := ""
+	if dep.UpdateNeeded {
+		target = dep.LatestVersion
+	}
+
+	vulns, err := du.vulnDB.Vulnerabilities(dep.Name, dep.CurrentVersion, target)
+	if err != nil {
+		du.logger.Warn("Could not check vulnerabilities of %s: %v", dep.Name, err)
+		return
+	}
+
+	analysis.Vulnerabilities = vulns
+	if len(vulns) > 0 {
+		du.logger.Warn("%s@%s is affected by %s", dep.Name, dep.CurrentVersion, vulnerabilityIDs(vulns, false))
+	}
+}
+
+// decideOnVulnerabilities approves updates that fix known vulnerabilities when
+// no other signal is decisive
+func (du *DependencyUpdater) decideOnVulnerabilities(analysis *models.UpdateAnalysis) {
+	fixed := vulnerabilityIDs(analysis.Vulnerabilities, true)
+	if fixed == "" {
+		return
+	}
+
+	reason := "fixes " + fixed
+	switch {
+	case analysis.ShouldUpdate:
+		analysis.UpdateReason = reason + ", " + analysis.UpdateReason
+	case strings.HasPrefix(analysis.RejectionReason, reasonNoImprovements):
+		analysis.ShouldUpdate = true
+		analysis.UpdateReason = reason
+		analysis.RejectionReason = ""
+	}
+}
+
+// preferFixVersion analyzes the lowest version fixing the advisories that may
+// be reachable when the proposed update is rejected or leaves one of them open.
+// That version replaces the proposed one when it is approved.
+func (du *DependencyUpdater) preferFixVersion(analysis *models.UpdateAnalysis) *models.UpdateAnalysis {
+	target, ids := du.fixTarget(analysis)
+	if target == "" {
+		return analysis
+	}
+
+	dep := analysis.Dependency
+	proposed := dep.LatestVersion
+	dep.LatestVersion = target
+	fixAnalysis, err := du.analyzeUpdate(dep)
+	if err != nil || !fixAnalysis.ShouldUpdate {
+		du.logger.Info("Keeping %s@%s, the update to %s fixing %s is not approved either",
+			dep.Name, proposed, target, strings.Join(ids, ", "))
+		dep.LatestVersion = proposed
+		return analysis
+	}
+
+	fixed := "lowest version fixing " + strings.Join(ids, ", ")
+	if analysis.ShouldUpdate {
+		fixAnalysis.UpdateReason += fmt.Sprintf(" (%s, which %s does not fix)", fixed, proposed)
+	} else {
+		fixAnalysis.UpdateReason += fmt.Sprintf(" (%s, as %s is rejected: %s)", fixed, proposed, analysis.RejectionReason)
+	}
+	du.logger.Info("Proposing %s@%s instead of %s as the %s", dep.Name, target, proposed, fixed)
+	return fixAnalysis
+}
+
+// fixTarget returns the version to analyze instead of a rejected or still
+// vulnerable update, with the advisories it fixes; empty when there is none
+func (du *DependencyUpdater) fixTarget(analysis *models.UpdateAnalysis) (target string, ids []string) {
+	if du.vulnDB == nil {
+		return "", nil
+	}
+
+	fix, ids := lowestFix(analysis.Vulnerabilities)
+	if fix == "" || (analysis.ShouldUpdate && fixedByUpdate(analysis.Vulnerabilities, ids)) {
+		return "", nil
+	}
+
+	return du.lowestFixVersion(analysis.Dependency, fix, ids), ids
+}
+
+// lowestFixVersion returns the lowest version from fix up to the proposed one
+// that none of the advisories affects, empty when there is none below the
+// proposed version
+func (du *DependencyUpdater) lowestFixVersion(dep *models.Dependency, fix string, ids []string) string {
+	for _, version := range dep.Versions {
+		if semver.Compare(version, dep.LatestVersion) >= 0 {
+			break
+		}
+		if semver.Compare(version, fix) >= 0 && du.fixesAll(dep.Name, version, ids) {
+			return version
+		}
+	}
+	return ""
+}
+
+// fixedByUpdate reports whether the update fixes all of the advisories
+func fixedByUpdate(vulns []models.Vulnerability, ids []string) bool {
+	for i := range vulns {
+		if slices.Contains(ids, vulns[i].ID) && !vulns[i].FixedByUpdate {
+			return false
+		}
+	}
+	return true
+}
+
+// lowestFix returns the lowest version past every fix of the advisories that
+// may be reachable, with their IDs
+func lowestFix(vulns []models.Vulnerability) (fix string, ids []string) {
+	for i := range vulns {
+		vuln := &vulns[i]
+		if vuln.Reachability == models.ReachabilityNotImported || vuln.FixedIn == "" {
+			continue
+		}
+		ids = append(ids, vuln.ID)
+		if fix == "" || semver.Compare(vuln.FixedIn, fix) > 0 {
+			fix = vuln.FixedIn
+		}
+	}
+	return fix, ids
+}
+
+// fixesAll reports whether none of the advisories affects a version, which a
+// later advisory range may affect again
+func (du *DependencyUpdater) fixesAll(module, version string, ids []string) bool {
+	remaining, err := du.vulnDB.Vulnerabilities(module, version, "")
+	if err != nil {
+		return false
+	}
+	for i := range remaining {
+		if slices.Contains(ids, remaining[i].ID) {
+			return false
+		}
+	}
+	return true
+}
+
+// prioritize raises the update priority for every fixed vulnerability, the more
+// so the closer the project is to calling the vulnerable code
+func prioritize(analysis *models.UpdateAnalysis) {
+	for _, vuln := range analysis.Vulnerabilities {
+		if vuln.FixedByUpdate {
+			analysis.Priority += reachabilityPriority(vuln.Reachability)
+		}
+	}
+}
+
+// vulnerabilityFactor scores the advisories fixed or left open by an update,
+// weighted by their reachability
+func vulnerabilityFactor(vulns []models.Vulnerability) (models.RiskFactor, bool) {
+	if len(vulns) == 0 {
+		return models.RiskFactor{}, false
+	}
+
+	points := 0
+	fixed, open := 0, 0
+	for _, vuln := range vulns {
+		weight := reachabilityPriority(vuln.Reachability) * 10
+		if vuln.FixedByUpdate {
+			fixed++
+			points -= weight
+		} else {
+			open++
+			points += weight
+		}
+	}
+
+	return models.RiskFactor{
+		Name:   factorVulnerabilities,
+		Points: points,
+		Detail: fmt.Sprintf("fixes %d, leaves %d known vulnerabilities", fixed, open),
+	}, true
+}
+
+// vulnerabilityIDs lists the advisory IDs, only those fixed by the update if requested
+func vulnerabilityIDs(vulns []models.Vulnerability, fixedOnly bool) string {
+	var ids []string
+	for _, vuln := range vulns {
+		if !fixedOnly || vuln.FixedByUpdate {
+			ids = append(ids, vuln.ID)
+		}
+	}
+	return strings.Join(ids, ", ")
+}
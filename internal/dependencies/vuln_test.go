@@ -0,0 +1,112 @@
+package dependencies
+
+import (
+	"cmp"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+	"github.com/moeryomenko/gupdeps/internal/vulndb"
+)
+
+// testAdvisories maps advisory IDs to their OSV range events for example.com/mod
+var testAdvisories = map[string]string{
+	"GO-1": `{"introduced": "0"}, {"fixed": "1.2.0"}`,
+	"GO-2": `{"introduced": "0"}, {"fixed": "1.3.0"}`,
+	"GO-3": `{"introduced": "0"}, {"fixed": "2.0.0"}`,
+	// Fixed in 1.1.0, then affected again until 1.5.0
+	"GO-4": `{"introduced": "0"}, {"fixed": "1.1.0"}, {"introduced": "1.1.0"}, {"fixed": "1.5.0"}`,
+	// Fixed in 1.2.0, then affected again from 1.4.0 without a fix
+	"GO-5": `{"introduced": "0"}, {"fixed": "1.2.0"}, {"introduced": "1.4.0"}`,
+}
+
+func TestFixTarget(t *testing.T) {
+	du := &DependencyUpdater{vulnDB: openTestVulnDB(t)}
+	versions := []string{"v1.1.0", "v1.2.0", "v1.3.0", "v1.4.0", "v1.5.0", "v2.0.0"}
+
+	tests := []struct {
+		name        string
+		ids         []string
+		notImported []string // advisories whose packages the project does not import
+		latest      string   // proposed version, v2.0.0 when empty
+		approved    bool
+		want        string
+	}{
+		{name: "single advisory", ids: []string{"GO-1"}, want: "v1.2.0"},
+		{name: "highest fix of several", ids: []string{"GO-1", "GO-2"}, want: "v1.3.0"},
+		{name: "not imported advisory ignored", ids: []string{"GO-1", "GO-2"}, notImported: []string{"GO-2"}, want: "v1.2.0"},
+		{name: "fix is the rejected target", ids: []string{"GO-3"}},
+		{name: "fix affected again", ids: []string{"GO-4"}, want: "v1.5.0"},
+		{name: "fix above the proposed version", ids: []string{"GO-2"}, latest: "v1.2.0"},
+		{name: "approved update fixing them", ids: []string{"GO-1"}, approved: true},
+		{name: "approved update leaving one open", ids: []string{"GO-1", "GO-5"}, approved: true, want: "v1.2.0"},
+		{name: "no advisory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			latest := cmp.Or(tt.latest, "v2.0.0")
+			dep := &models.Dependency{
+				Name:           "example.com/mod",
+				CurrentVersion: "v1.0.0",
+				LatestVersion:  latest,
+				Versions:       versions,
+			}
+			analysis := &models.UpdateAnalysis{Dependency: dep, ShouldUpdate: tt.approved}
+			for _, id := range tt.ids {
+				fix := fixVersion(t, du.vulnDB, id)
+				vuln := models.Vulnerability{ID: id, FixedIn: fix, FixedByUpdate: du.fixesAll(dep.Name, latest, []string{id})}
+				if slices.Contains(tt.notImported, id) {
+					vuln.Reachability = models.ReachabilityNotImported
+				}
+				analysis.Vulnerabilities = append(analysis.Vulnerabilities, vuln)
+			}
+
+			if target, _ := du.fixTarget(analysis); target != tt.want {
+				t.Errorf("target = %q, want %q", target, tt.want)
+			}
+		})
+	}
+}
+
+// fixVersion returns the version fixing an advisory for v1.0.0 of the test module
+func fixVersion(t *testing.T, db *vulndb.DB, id string) string {
+	t.Helper()
+
+	vulns, err := db.Vulnerabilities("example.com/mod", "v1.0.0", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range vulns {
+		if vulns[i].ID == id {
+			return vulns[i].FixedIn
+		}
+	}
+	t.Fatalf("%s does not affect v1.0.0", id)
+	return ""
+}
+
+// openTestVulnDB writes the test advisories as a local vulnerability database
+func openTestVulnDB(t *testing.T) *vulndb.DB {
+	t.Helper()
+
+	dir := t.TempDir()
+	var refs []string
+	for id, events := range testAdvisories {
+		refs = append(refs, `{"id": "`+id+`"}`)
+		writeTestFile(t, dir, filepath.Join("ID", id+".json"), `{"id": "`+id+`", "affected": [{
+			"package": {"name": "example.com/mod"},
+			"ranges": [{"type": "SEMVER", "events": [`+events+`]}]
+		}]}`)
+	}
+	writeTestFile(t, dir, filepath.Join("index", "modules.json"),
+		`[{"path": "example.com/mod", "vulns": [`+strings.Join(refs, ", ")+`]}]`)
+
+	db, err := vulndb.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
@@ -4,24 +4,189 @@ import "time"
 
 // Dependency represents a Go module dependency
 type Dependency struct {
-	Name           string `json:"name"`
-	CurrentVersion string `json:"current_version"`
-	LatestVersion  string `json:"latest_version"`
-	UpdateNeeded   bool   `json:"update_needed"`
+	Name           string          `json:"name"`
+	CurrentVersion string          `json:"current_version"`
+	LatestVersion  string          `json:"latest_version"`
+	UpdateNeeded   bool            `json:"update_needed"`
+	Versions       []string        `json:"versions,omitempty"` // versions newer than the current one, oldest first
+	Pending        *PendingVersion `json:"pending,omitempty"`
+	Ignored        *IgnoredVersion `json:"ignored,omitempty"`
+	Retracted      []string        `json:"retracted,omitempty"` // rationale when the current version is retracted
+}
+
+// IgnoredVersion represents the newest release skipped by the ignore list
+type IgnoredVersion struct {
+	Version string `json:"version"`
+	Reason  string `json:"reason"`
+}
+
+// PendingVersion represents a release held back until it reaches the minimum release age
+type PendingVersion struct {
+	Version string    `json:"version"`
+	Until   time.Time `json:"until"`
 }
 
 // CommitInfo represents commit information
 type CommitInfo struct {
-	Hash    string
-	Message string
-	Date    time.Time
+	Hash    string    `json:"hash"`
+	Message string    `json:"message"`
+	Date    time.Time `json:"date"`
+
+	// Conventional Commits fields, set when the message follows the specification
+	Conventional bool   `json:"conventional,omitempty"`
+	Type         string `json:"type,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+	Breaking     bool   `json:"breaking,omitempty"`
+
+	// Category is the classification assigned by the analyzer, empty if none
+	Category string `json:"category,omitempty"`
 }
 
 // UpdateAnalysis represents the analysis result for an update
 type UpdateAnalysis struct {
-	Dependency      *Dependency
-	Commits         []CommitInfo
-	ShouldUpdate    bool
-	UpdateReason    string
-	RejectionReason string
+	Dependency      *Dependency      `json:"-"`
+	Commits         []CommitInfo     `json:"commits,omitempty"`
+	ShouldUpdate    bool             `json:"should_update"`
+	UpdateReason    string           `json:"update_reason,omitempty"`
+	RejectionReason string           `json:"rejection_reason,omitempty"`
+	Changelog       *Changelog       `json:"changelog,omitempty"`
+	Vulnerabilities []Vulnerability  `json:"vulnerabilities,omitempty"`
+	Priority        int              `json:"priority,omitempty"` // urgency of the update, higher is more urgent
+	Risk            *RiskAssessment  `json:"risk,omitempty"`
+	APIDiff         *APIDiff         `json:"api_diff,omitempty"`
+	License         *LicenseChange   `json:"license,omitempty"`
+	GoRequirement   *GoRequirement   `json:"go_requirement,omitempty"`
+	ModuleGraph     *ModuleGraphDiff `json:"module_graph,omitempty"`
+	SizeImpact      *SizeImpact      `json:"size_impact,omitempty"`
+	Outcome         *UpdateOutcome   `json:"outcome,omitempty"`
+}
+
+// ChangelogGroups lists the Keep a Changelog entry groups in their canonical order
+var ChangelogGroups = []string{"Added", "Changed", "Deprecated", "Removed", "Fixed", "Security"}
+
+// Changelog represents the curated release notes between two versions
+type Changelog struct {
+	Source   string             `json:"source"` // file or tags the sections were read from
+	Sections []ChangelogSection `json:"sections,omitempty"`
+	TagNotes []TagNote          `json:"tag_notes,omitempty"`
+}
+
+// ChangelogSection represents the entries of a single released version
+type ChangelogSection struct {
+	Version string              `json:"version"`
+	Date    string              `json:"date,omitempty"`
+	Entries map[string][]string `json:"entries,omitempty"` // group name -> entries
+}
+
+// TagNote represents the message of an annotated release tag
+type TagNote struct {
+	Tag     string `json:"tag"`
+	Message string `json:"message"`
+}
+
+// Vulnerability represents a known advisory affecting the current version of a dependency
+type Vulnerability struct {
+	ID            string              `json:"id"`
+	Aliases       []string            `json:"aliases,omitempty"`
+	Summary       string              `json:"summary"`
+	FixedIn       string              `json:"fixed_in,omitempty"` // first version that fixes the advisory, empty if unfixed
+	FixedByUpdate bool                `json:"fixed_by_update"`    // the target version is not affected
+	Packages      []VulnerablePackage `json:"packages,omitempty"`
+	Reachability  Reachability        `json:"reachability,omitempty"`
+}
+
+// Reachability describes whether the project uses the vulnerable code
+type Reachability string
+
+// Reachability values, from the most to the least exposed
+const (
+	ReachabilityCalled      Reachability = "called"
+	ReachabilityImported    Reachability = "imported"
+	ReachabilityNotImported Reachability = "not imported"
+	ReachabilityUnknown     Reachability = ""
+)
+
+// VulnerablePackage represents a package and the symbols an advisory applies to
+type VulnerablePackage struct {
+	Path    string   `json:"path"`
+	Symbols []string `json:"symbols,omitempty"` // empty when the whole package is affected
+}
+
+// RiskLevel classifies a risk score
+type RiskLevel string
+
+// Risk levels in increasing order of severity
+const (
+	RiskLow      RiskLevel = "low"
+	RiskMedium   RiskLevel = "medium"
+	RiskHigh     RiskLevel = "high"
+	RiskCritical RiskLevel = "critical"
+)
+
+// RiskFactor is a single signal contributing to the risk score
+type RiskFactor struct {
+	Name   string `json:"name"`
+	Points int    `json:"points"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// RiskAssessment represents the numeric risk of an update and the factors behind it
+type RiskAssessment struct {
+	Score   int          `json:"score"`
+	Level   RiskLevel    `json:"level"`
+	Factors []RiskFactor `json:"factors,omitempty"`
+}
+
+// APIDiff represents the exported API changes between two module versions
+type APIDiff struct {
+	Incompatible []string `json:"incompatible,omitempty"` // removed or changed symbols
+	Compatible   []string `json:"compatible,omitempty"`   // added symbols
+}
+
+// LicenseChange represents the SPDX license expressions at the current and target
+// versions of a dependency; an empty expression means the license is unknown
+type LicenseChange struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// GoRequirement represents the go and toolchain directives of a dependency's go.mod
+// at the current and target versions; empty values mean the directive is absent
+type GoRequirement struct {
+	From          string `json:"from,omitempty"`
+	To            string `json:"to,omitempty"`
+	FromToolchain string `json:"from_toolchain,omitempty"`
+	ToToolchain   string `json:"to_toolchain,omitempty"`
+}
+
+// ModuleGraphDiff represents the changes an update makes to the resolved module graph
+type ModuleGraphDiff struct {
+	Added      []ModuleChange `json:"added,omitempty"`
+	Removed    []ModuleChange `json:"removed,omitempty"`
+	Upgraded   []ModuleChange `json:"upgraded,omitempty"`
+	Downgraded []ModuleChange `json:"downgraded,omitempty"`
+}
+
+// ModuleChange represents a module whose selected version changes; From is empty
+// for added modules and To for removed ones
+type ModuleChange struct {
+	Path string `json:"path"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// SizeImpact represents the total size of the project's binaries and the number
+// of packages linked into them, before and after an update
+type SizeImpact struct {
+	SizeBefore     int64 `json:"size_before"`
+	SizeAfter      int64 `json:"size_after"`
+	PackagesBefore int   `json:"packages_before"`
+	PackagesAfter  int   `json:"packages_after"`
+}
+
+// UpdateOutcome represents the result of applying and verifying an update
+type UpdateOutcome struct {
+	Applied  bool   `json:"applied"`
+	Verified bool   `json:"verified"`
+	Output   string `json:"output,omitempty"` // apply or verification output captured on failure
 }
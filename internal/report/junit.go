@@ -0,0 +1,117 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// junitTestSuites and the types below model the JUnit XML schema understood by CI systems
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Output  string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// writeJUnit renders every dependency update as a test case: verified updates
+// pass, updates failing to apply or verify fail with the captured output, failed
+// analyses are errors and updates that were not verified are skipped
+func writeJUnit(w io.Writer, r *Report) error {
+	suite := junitTestSuite{
+		Name:      "dependency updates",
+		Timestamp: r.GeneratedAt.Format("2006-01-02T15:04:05"),
+	}
+
+	for i := range r.Dependencies {
+		entry := &r.Dependencies[i]
+		if entry.TargetVersion == "" && entry.Decision != DecisionError {
+			continue
+		}
+
+		testCase := junitCase(entry)
+		suite.Tests++
+		switch {
+		case testCase.Failure != nil:
+			suite.Failures++
+		case testCase.Error != nil:
+			suite.Errors++
+		case testCase.Skipped != nil:
+			suite.Skipped++
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	suites := junitTestSuites{
+		Name:     "gupdeps",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Skipped:  suite.Skipped,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// junitCase returns the test case of a dependency update
+func junitCase(entry *Entry) junitTestCase {
+	testCase := junitTestCase{
+		ClassName: entry.Module,
+		Name:      "update " + versionChange(entry),
+	}
+
+	switch outcome := entry.outcome(); {
+	case entry.Decision == DecisionError:
+		testCase.Error = &junitProblem{Message: "analysis failed", Output: entry.Error}
+	case entry.Decision != DecisionApproved:
+		testCase.Skipped = &junitSkipped{Message: string(entry.Decision) + ": " + entry.Reason}
+	case outcome == nil:
+		testCase.Skipped = &junitSkipped{Message: "not applied"}
+	case !outcome.Applied:
+		testCase.Failure = &junitProblem{Message: "update failed", Output: outcome.Output}
+	case !outcome.Verified:
+		testCase.Skipped = &junitSkipped{Message: "applied without verification"}
+	}
+
+	return testCase
+}
@@ -0,0 +1,181 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/moeryomenko/gupdeps/internal/dependencies"
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// markdownCommitLimit caps the commits listed per module to keep pull request bodies short
+const markdownCommitLimit = 50
+
+// decisionIcons prefixes decisions in the markdown summary table
+var decisionIcons = map[Decision]string{
+	DecisionUpToDate: "✔️",
+	DecisionHeld:     "⏳",
+	DecisionApproved: "✅",
+	DecisionRejected: "❌",
+	DecisionError:    "⚠️",
+}
+
+// writeMarkdown renders the report as a summary table followed by a collapsible
+// section per analyzed module, suitable for a pull request body
+func writeMarkdown(w io.Writer, r *Report) error {
+	var b strings.Builder
+
+	b.WriteString("## Dependency updates\n\n")
+	fmt.Fprintf(&b, "%d dependencies: %d approved, %d rejected, %d held back, %d up to date, %d failed to analyze.\n",
+		r.Summary.Total, r.Summary.Approved, r.Summary.Rejected, r.Summary.Held, r.Summary.UpToDate, r.Summary.Errors)
+
+	writeMarkdownTable(&b, r)
+
+	for i := range r.Dependencies {
+		if entry := &r.Dependencies[i]; entry.Analysis != nil && entry.TargetVersion != "" {
+			writeMarkdownDetails(&b, entry)
+		}
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write markdown report: %w", err)
+	}
+	return nil
+}
+
+// writeMarkdownTable writes a row per dependency with a newer version
+func writeMarkdownTable(b *strings.Builder, r *Report) {
+	rows := 0
+	for i := range r.Dependencies {
+		entry := &r.Dependencies[i]
+		if entry.Decision == DecisionUpToDate {
+			continue
+		}
+
+		if rows == 0 {
+			b.WriteString("\n| Module | Update | Bump | Decision | Risk |\n")
+			b.WriteString("|--------|--------|------|----------|------|\n")
+		}
+		rows++
+
+		fmt.Fprintf(b, "| `%s` | %s | %s | %s %s | %s |\n",
+			entry.Module, versionChange(entry), entry.Bump,
+			decisionIcons[entry.Decision], entry.Decision, markdownRisk(entry.Analysis))
+	}
+}
+
+// writeMarkdownDetails writes the collapsible section of an analyzed module
+func writeMarkdownDetails(b *strings.Builder, entry *Entry) {
+	analysis := entry.Analysis
+
+	fmt.Fprintf(b, "\n<details>\n<summary><code>%s</code> %s</summary>\n\n", entry.Module, versionChange(entry))
+	fmt.Fprintf(b, "**Decision:** %s %s: %s\n", decisionIcons[entry.Decision], entry.Decision, escapeMarkdown(entry.Reason))
+
+	if risk := analysis.Risk; risk != nil {
+		fmt.Fprintf(b, "\n**Risk:** %s\n\n", markdownRisk(analysis))
+		for _, factor := range risk.Factors {
+			fmt.Fprintf(b, "- %+d %s: %s\n", factor.Points, factor.Name, escapeMarkdown(factor.Detail))
+		}
+	}
+
+	if len(analysis.Commits) > 0 {
+		fmt.Fprintf(b, "\n**Commits** (%d)\n\n", len(analysis.Commits))
+		for i := range analysis.Commits {
+			if i == markdownCommitLimit {
+				fmt.Fprintf(b, "- … and %d more\n", len(analysis.Commits)-i)
+				break
+			}
+			b.WriteString("- " + markdownCommit(entry.Module, &analysis.Commits[i]) + "\n")
+		}
+	}
+
+	writeMarkdownChangelog(b, analysis.Changelog)
+
+	b.WriteString("\n</details>\n")
+}
+
+// writeMarkdownChangelog writes the changelog excerpt between the versions
+func writeMarkdownChangelog(b *strings.Builder, changelog *models.Changelog) {
+	if changelog == nil || len(changelog.Sections)+len(changelog.TagNotes) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "\n**Changelog** (%s)\n\n", escapeMarkdown(changelog.Source))
+	for _, section := range changelog.Sections {
+		heading := "**" + escapeMarkdown(section.Version) + "**"
+		if section.Date != "" {
+			heading += " (" + escapeMarkdown(section.Date) + ")"
+		}
+		b.WriteString("- " + heading + "\n")
+
+		for _, group := range models.ChangelogGroups {
+			for _, entry := range section.Entries[group] {
+				fmt.Fprintf(b, "  - %s: %s\n", group, escapeMarkdown(entry))
+			}
+		}
+	}
+
+	if len(changelog.Sections) > 0 {
+		return
+	}
+
+	for _, note := range changelog.TagNotes {
+		subject, _, _ := strings.Cut(note.Message, "\n")
+		fmt.Fprintf(b, "- **%s** %s\n", escapeMarkdown(note.Tag), escapeMarkdown(subject))
+	}
+}
+
+// versionChange describes the version change of an entry, or the release held back
+func versionChange(entry *Entry) string {
+	switch {
+	case entry.TargetVersion != "":
+		return entry.CurrentVersion + " → " + entry.TargetVersion
+	case entry.Pending != nil:
+		return entry.CurrentVersion + " (" + entry.Pending.Version + " pending)"
+	case entry.Ignored != nil:
+		return entry.CurrentVersion + " (" + entry.Ignored.Version + " ignored)"
+	default:
+		return entry.CurrentVersion
+	}
+}
+
+// markdownRisk returns the risk level and score of an analysis, if assessed
+func markdownRisk(analysis *models.UpdateAnalysis) string {
+	if analysis == nil || analysis.Risk == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s (%d)", analysis.Risk.Level, analysis.Risk.Score)
+}
+
+// markdownCommit returns a commit subject with its abbreviated hash, linked to the
+// repository's web UI when the host is known
+func markdownCommit(module string, commit *models.CommitInfo) string {
+	subject, _, _ := strings.Cut(commit.Message, "\n")
+	subject = escapeMarkdown(subject)
+	if commit.Category != "" {
+		subject = "[" + escapeMarkdown(commit.Category) + "] " + subject
+	}
+
+	hash := "`" + commit.Hash[:min(7, len(commit.Hash))] + "`"
+	if url := dependencies.CommitURL(module, commit.Hash); url != "" {
+		hash = "[" + hash + "](" + url + ")"
+	}
+
+	return hash + " " + subject
+}
+
+// markdownEscaper escapes the HTML and markdown metacharacters of free text,
+// such as commit subjects, which could otherwise break out of the table or
+// the collapsible sections
+var markdownEscaper = strings.NewReplacer(
+	"&", "&amp;", "<", "&lt;", ">", "&gt;",
+	"\\", "\\\\", "`", "\\`", "*", "\\*", "_", "\\_",
+	"[", "\\[", "]", "\\]", "|", "\\|", "#", "\\#",
+	"\r", " ", "\n", " ",
+)
+
+// escapeMarkdown returns free text safe to write inline in the markdown report
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
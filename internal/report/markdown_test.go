@@ -0,0 +1,49 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+func TestMarkdownEscapesFreeText(t *testing.T) {
+	dep := &models.Dependency{
+		Name:           "example.com/a",
+		CurrentVersion: "v1.0.0",
+		LatestVersion:  "v1.1.0",
+		UpdateNeeded:   true,
+	}
+	analysis := &models.UpdateAnalysis{
+		Dependency: dep,
+		Commits: []models.CommitInfo{
+			{Hash: "0123456789abcdef", Message: "fix: close </details> and <b>tags</b> | `code` *bold* [link](x)\n\nbody"},
+		},
+		RejectionReason: "Contains 1 breaking changes | </details><script>",
+		Risk:            &models.RiskAssessment{Score: 50, Level: models.RiskHigh},
+	}
+
+	var b strings.Builder
+	rep := New("/project", []*models.Dependency{dep}, []*models.UpdateAnalysis{analysis}, []error{nil})
+	if err := writeMarkdown(&b, rep); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+
+	if n := strings.Count(out, "</details>"); n != 1 {
+		t.Errorf("the report must only close its own section, found %d </details>:\n%s", n, out)
+	}
+	for _, raw := range []string{"<b>", "<script>", "`code`", "*bold*", "[link](x)"} {
+		if strings.Contains(out, raw) {
+			t.Errorf("the report contains the unescaped %q:\n%s", raw, out)
+		}
+	}
+	for _, want := range []string{
+		"fix: close &lt;/details&gt; and &lt;b&gt;tags&lt;/b&gt; \\| \\`code\\` \\*bold\\* \\[link\\](x)",
+		"Contains 1 breaking changes \\| &lt;/details&gt;&lt;script&gt;",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("the report lacks %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/moeryomenko/gupdeps/internal/dependencies"
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// SchemaVersion is the version of the report schema. It is incremented whenever
+// a field is removed, renamed or changes meaning; new fields may be added
+// without a version change.
+const SchemaVersion = 1
+
+// Decision is the verdict on a single dependency
+type Decision string
+
+// Decisions a report entry can carry
+const (
+	DecisionUpToDate Decision = "up-to-date" // no newer version
+	DecisionHeld     Decision = "held"       // newer versions are pending or ignored
+	DecisionApproved Decision = "approved"
+	DecisionRejected Decision = "rejected"
+	DecisionError    Decision = "error" // the analysis failed
+)
+
+// Report is the structured result of a gupdeps run
+type Report struct {
+	SchemaVersion int       `json:"schema_version"`
+	GeneratedAt   time.Time `json:"generated_at"`
+	Project       string    `json:"project"`
+	Summary       Summary   `json:"summary"`
+	Dependencies  []Entry   `json:"dependencies"`
+}
+
+// Summary counts the entries of a report by decision and outcome
+type Summary struct {
+	Total    int `json:"total"`
+	UpToDate int `json:"up_to_date"`
+	Held     int `json:"held"`
+	Approved int `json:"approved"`
+	Rejected int `json:"rejected"`
+	Errors   int `json:"errors"`
+	Applied  int `json:"applied"`
+	Failed   int `json:"failed"` // updates that failed to apply or verify
+}
+
+// Entry is the report of a single dependency
+type Entry struct {
+	Module         string                 `json:"module"`
+	CurrentVersion string                 `json:"current_version"`
+	TargetVersion  string                 `json:"target_version,omitempty"`
+	Bump           string                 `json:"bump,omitempty"` // major, minor, patch or prerelease
+	Decision       Decision               `json:"decision"`
+	Reason         string                 `json:"reason,omitempty"`
+	Error          string                 `json:"error,omitempty"`
+	Pending        *models.PendingVersion `json:"pending,omitempty"`
+	Ignored        *models.IgnoredVersion `json:"ignored,omitempty"`
+	Retracted      []string               `json:"retracted,omitempty"` // rationale when the current version is retracted
+	Analysis       *models.UpdateAnalysis `json:"analysis,omitempty"`
+}
+
+// New builds a report from the analyses of dependencies once the approved updates
+// have been applied; analyses and errs are indexed like deps, with a nil analysis
+// where the analysis failed
+func New(project string, deps []*models.Dependency, analyses []*models.UpdateAnalysis, errs []error) *Report {
+	r := &Report{
+		SchemaVersion: SchemaVersion,
+		GeneratedAt:   time.Now().UTC(),
+		Project:       project,
+		Dependencies:  make([]Entry, 0, len(deps)),
+	}
+
+	for i, dep := range deps {
+		entry := Entry{
+			Module:         dep.Name,
+			CurrentVersion: dep.CurrentVersion,
+			Pending:        dep.Pending,
+			Ignored:        dep.Ignored,
+			Retracted:      dep.Retracted,
+			Analysis:       analyses[i],
+		}
+		if dep.UpdateNeeded {
+			entry.TargetVersion = dep.LatestVersion
+			entry.Bump = dependencies.BumpKind(dep.CurrentVersion, dep.LatestVersion)
+		}
+
+		switch analysis := analyses[i]; {
+		case errs[i] != nil:
+			entry.Decision, entry.Error = DecisionError, errs[i].Error()
+		case !dep.UpdateNeeded && (dep.Pending != nil || dep.Ignored != nil):
+			entry.Decision = DecisionHeld
+		case !dep.UpdateNeeded:
+			entry.Decision = DecisionUpToDate
+		case analysis.ShouldUpdate:
+			entry.Decision, entry.Reason = DecisionApproved, analysis.UpdateReason
+		default:
+			entry.Decision, entry.Reason = DecisionRejected, analysis.RejectionReason
+		}
+
+		r.add(&entry)
+	}
+
+	return r
+}
+
+// add appends an entry and counts it in the summary
+func (r *Report) add(entry *Entry) {
+	r.Dependencies = append(r.Dependencies, *entry)
+	r.Summary.Total++
+
+	switch entry.Decision {
+	case DecisionUpToDate:
+		r.Summary.UpToDate++
+	case DecisionHeld:
+		r.Summary.Held++
+	case DecisionApproved:
+		r.Summary.Approved++
+	case DecisionRejected:
+		r.Summary.Rejected++
+	case DecisionError:
+		r.Summary.Errors++
+	}
+
+	switch outcome := entry.outcome(); {
+	case outcome == nil:
+	case outcome.Applied:
+		r.Summary.Applied++
+	default:
+		r.Summary.Failed++
+	}
+}
+
+// outcome returns the apply and verify outcome of an entry, nil when it was not applied
+func (e *Entry) outcome() *models.UpdateOutcome {
+	if e.Analysis == nil {
+		return nil
+	}
+	return e.Analysis.Outcome
+}
+
+// Write renders the report in a format
+func Write(w io.Writer, format string, r *Report) error {
+	switch format {
+	case "json":
+		return writeJSON(w, r)
+	case "markdown":
+		return writeMarkdown(w, r)
+	case "sarif":
+		return writeSARIF(w, r)
+	case "junit":
+		return writeJUnit(w, r)
+	default:
+		return fmt.Errorf("unsupported report format %q", format)
+	}
+}
@@ -0,0 +1,272 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// SARIF rule identifiers, one per finding type
+const (
+	ruleRejectedUpdate = "gupdeps/rejected-update"
+	ruleHighRiskUpdate = "gupdeps/high-risk-update"
+	ruleRetracted      = "gupdeps/retracted-version"
+	ruleVulnerable     = "gupdeps/vulnerable-dependency"
+)
+
+// sarifRules describes the findings gupdeps reports, in rule index order
+var sarifRules = []sarifRule{
+	{
+		ID:                   ruleRejectedUpdate,
+		Name:                 "RejectedUpdate",
+		ShortDescription:     sarifMessage{Text: "Dependency update rejected"},
+		FullDescription:      sarifMessage{Text: "A newer version of the dependency exists but the analysis rejected it; review it manually."},
+		DefaultConfiguration: sarifConfiguration{Level: "warning"},
+	},
+	{
+		ID:                   ruleHighRiskUpdate,
+		Name:                 "HighRiskUpdate",
+		ShortDescription:     sarifMessage{Text: "High-risk dependency update"},
+		FullDescription:      sarifMessage{Text: "The update of the dependency scores a high or critical risk."},
+		DefaultConfiguration: sarifConfiguration{Level: "warning"},
+	},
+	{
+		ID:                   ruleRetracted,
+		Name:                 "RetractedVersion",
+		ShortDescription:     sarifMessage{Text: "Retracted dependency version"},
+		FullDescription:      sarifMessage{Text: "The required version of the dependency was retracted by its author."},
+		DefaultConfiguration: sarifConfiguration{Level: "error"},
+	},
+	{
+		ID:                   ruleVulnerable,
+		Name:                 "VulnerableDependency",
+		ShortDescription:     sarifMessage{Text: "Vulnerable dependency"},
+		FullDescription:      sarifMessage{Text: "The required version of the dependency is affected by a known vulnerability."},
+		DefaultConfiguration: sarifConfiguration{Level: "error"},
+	},
+}
+
+// sarifLog and the types below model the subset of SARIF 2.1.0 gupdeps emits
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                   `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactURI `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult               `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	FullDescription      sarifMessage       `json:"fullDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactURI `json:"artifactLocation"`
+	Region           *sarifRegion     `json:"region,omitempty"`
+}
+
+type sarifArtifactURI struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+// writeSARIF renders the findings of the report as a SARIF 2.1.0 log, with every
+// result pointing at the require line of its module in go.mod
+func writeSARIF(w io.Writer, r *Report) error {
+	regions, err := requireRegions(filepath.Join(r.Project, "go.mod"))
+	if err != nil {
+		return err
+	}
+
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "gupdeps",
+			InformationURI: "https://github.com/moeryomenko/gupdeps",
+			Rules:          sarifRules,
+		}},
+		OriginalURIBaseIDs: map[string]sarifArtifactURI{
+			"%SRCROOT%": {URI: "file://" + filepath.ToSlash(r.Project) + "/"},
+		},
+		Results: []sarifResult{},
+	}
+
+	for i := range r.Dependencies {
+		entry := &r.Dependencies[i]
+		location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactURI{URI: "go.mod", URIBaseID: "%SRCROOT%"},
+			Region:           regions[entry.Module],
+		}}
+
+		for _, result := range entryResults(entry) {
+			result.Locations = []sarifLocation{location}
+			run.Results = append(run.Results, result)
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write SARIF report: %w", err)
+	}
+	return nil
+}
+
+// entryResults returns the findings of a dependency
+func entryResults(entry *Entry) []sarifResult {
+	var results []sarifResult
+
+	if len(entry.Retracted) > 0 {
+		results = append(results, newResult(ruleRetracted, "", entry,
+			fmt.Sprintf("%s@%s is retracted: %s", entry.Module, entry.CurrentVersion, strings.Join(entry.Retracted, "; "))))
+	}
+
+	analysis := entry.Analysis
+	if analysis == nil {
+		return results
+	}
+
+	if entry.Decision == DecisionRejected {
+		results = append(results, newResult(ruleRejectedUpdate, "", entry,
+			fmt.Sprintf("Update of %s to %s rejected: %s", entry.Module, entry.TargetVersion, entry.Reason)))
+	}
+
+	if risk := analysis.Risk; risk != nil && (risk.Level == models.RiskHigh || risk.Level == models.RiskCritical) {
+		level := "warning"
+		if risk.Level == models.RiskCritical {
+			level = "error"
+		}
+		results = append(results, newResult(ruleHighRiskUpdate, level, entry,
+			fmt.Sprintf("Update of %s to %s has a %s risk score of %d", entry.Module, entry.TargetVersion, risk.Level, risk.Score)))
+	}
+
+	for i := range analysis.Vulnerabilities {
+		vuln := &analysis.Vulnerabilities[i]
+		result := newResult(ruleVulnerable, "", entry, vulnerabilityMessage(entry, vuln))
+		result.PartialFingerprints["vulnerability"] = vuln.ID
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// newResult creates a result of a rule for a dependency, at the rule's default
+// level unless one is given
+func newResult(ruleID, level string, entry *Entry, message string) sarifResult {
+	index := 0
+	for i := range sarifRules {
+		if sarifRules[i].ID == ruleID {
+			index = i
+		}
+	}
+	if level == "" {
+		level = sarifRules[index].DefaultConfiguration.Level
+	}
+
+	return sarifResult{
+		RuleID:              ruleID,
+		RuleIndex:           index,
+		Level:               level,
+		Message:             sarifMessage{Text: message},
+		PartialFingerprints: map[string]string{"module": entry.Module},
+	}
+}
+
+// vulnerabilityMessage describes an advisory and whether the update fixes it
+func vulnerabilityMessage(entry *Entry, vuln *models.Vulnerability) string {
+	message := fmt.Sprintf("%s@%s is affected by %s: %s", entry.Module, entry.CurrentVersion, vuln.ID, vuln.Summary)
+	switch {
+	case vuln.FixedByUpdate && entry.TargetVersion != "":
+		message += " (fixed by " + entry.TargetVersion + ")"
+	case vuln.FixedIn != "":
+		message += " (fixed in " + vuln.FixedIn + ")"
+	default:
+		message += " (no fix available)"
+	}
+	if vuln.Reachability != models.ReachabilityUnknown {
+		message += ", " + string(vuln.Reachability)
+	}
+	return message
+}
+
+// requireRegions returns the region of every require line in a go.mod file
+func requireRegions(goModPath string) (map[string]*sarifRegion, error) {
+	data, err := os.ReadFile(goModPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod: %w", err)
+	}
+
+	file, err := modfile.ParseLax(goModPath, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse go.mod: %w", err)
+	}
+
+	regions := make(map[string]*sarifRegion, len(file.Require))
+	for _, require := range file.Require {
+		start, end := require.Syntax.Start, require.Syntax.End
+		regions[require.Mod.Path] = &sarifRegion{
+			StartLine:   start.Line,
+			StartColumn: start.LineRune,
+			EndLine:     end.Line,
+			EndColumn:   end.LineRune,
+		}
+	}
+
+	return regions, nil
+}
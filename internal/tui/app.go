@@ -0,0 +1,444 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/moeryomenko/gupdeps/internal/dependencies"
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// status is the state of a dependency in the table
+type status int
+
+// Dependency states, from the start of the analysis to the end of an apply
+const (
+	statusQueued status = iota
+	statusApproved
+	statusRejected
+	statusHeld
+	statusUpToDate
+	statusError
+	statusApplied
+	statusFailed
+)
+
+// statusNames holds the labels of the states in the table
+var statusNames = map[status]string{
+	statusQueued:   "analyzing",
+	statusApproved: "approved",
+	statusRejected: "rejected",
+	statusHeld:     "held",
+	statusUpToDate: "up to date",
+	statusError:    "error",
+	statusApplied:  "applied",
+	statusFailed:   "failed",
+}
+
+// row is a dependency of the table
+type row struct {
+	dep      *models.Dependency
+	analysis *models.UpdateAnalysis
+	err      error
+	status   status
+	selected bool
+}
+
+// updatable reports whether the update of the row can be selected for applying
+func (r *row) updatable() bool {
+	return r.status == statusApproved || r.status == statusRejected
+}
+
+// result is the outcome of the analysis of a dependency
+type result struct {
+	index    int
+	analysis *models.UpdateAnalysis
+	err      error
+}
+
+// input is a key event read in the background
+type input struct {
+	event Event
+	err   error
+}
+
+// updater analyzes the dependencies and applies their updates
+type updater interface {
+	AnalyzeEach(deps []*models.Dependency, done func(i int, analysis *models.UpdateAnalysis, err error))
+	BeginSession() (session, error)
+}
+
+// session applies updates together, rolling all of them back on failure
+type session interface {
+	Apply(dep *models.Dependency) error
+	Tidy() error
+	Commit() error
+}
+
+// dependencyUpdater adapts the dependency updater to the UI
+type dependencyUpdater struct {
+	*dependencies.DependencyUpdater
+}
+
+// BeginSession starts an update session
+func (u dependencyUpdater) BeginSession() (session, error) {
+	s, err := u.DependencyUpdater.BeginSession()
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// spinnerInterval is how often the status bar is refreshed while the analysis runs
+const spinnerInterval = 100 * time.Millisecond
+
+// App is the full-screen UI: a table of the outdated dependencies analyzed in
+// the background, with a detail pane for the update under the cursor
+type App struct {
+	updater updater
+	project string
+	rows    []*row
+	screen  Screen
+
+	cursor  int // index in the visible rows
+	offset  int // first visible row shown in the table
+	tab     int // tab of the detail pane
+	help    bool
+	confirm bool // waiting for the confirmation to apply the selection
+	message string
+	spinner int
+	pending int // analyses still running
+	results chan result
+}
+
+// New creates the UI for the dependencies of a project
+func New(du *dependencies.DependencyUpdater, project string, deps []*models.Dependency) *App {
+	return newApp(dependencyUpdater{du}, project, deps)
+}
+
+// newApp creates the UI with any updater
+func newApp(u updater, project string, deps []*models.Dependency) *App {
+	rows := make([]*row, len(deps))
+	for i, dep := range deps {
+		rows[i] = &row{dep: dep}
+	}
+
+	return &App{
+		updater: u,
+		project: project,
+		rows:    rows,
+		results: make(chan result, len(deps)),
+	}
+}
+
+// Run analyzes the dependencies in the background and handles key events
+// until the user quits or the events run out
+func (a *App) Run(events EventSource, screen Screen) error {
+	a.screen = screen
+	a.start()
+	inputs := readInputs(events)
+
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := a.draw(); err != nil {
+			return err
+		}
+
+		select {
+		case res := <-a.results:
+			a.finish(res)
+		case <-ticker.C:
+			if a.pending > 0 {
+				a.spinner++
+			}
+		case in := <-inputs:
+			if quit, err := a.input(in); quit || err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// readInputs reads the key events in the background, until the first error
+func readInputs(events EventSource) <-chan input {
+	inputs := make(chan input)
+	go func() {
+		for {
+			event, err := events.Next()
+			inputs <- input{event: event, err: err}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return inputs
+}
+
+// input handles a key event and reports whether the UI should quit; the end
+// of the events quits without an error
+func (a *App) input(in input) (bool, error) {
+	switch {
+	case errors.Is(in.err, io.EOF):
+		return true, nil
+	case in.err != nil:
+		return true, fmt.Errorf("failed to read keys: %w", in.err)
+	default:
+		return a.handle(in.event), nil
+	}
+}
+
+// draw renders the current state on the screen
+func (a *App) draw() error {
+	return a.screen.Draw(a.render(a.screen.Size()))
+}
+
+// start analyzes all dependencies in the background
+func (a *App) start() {
+	deps := make([]*models.Dependency, len(a.rows))
+	for i, r := range a.rows {
+		deps[i] = r.dep
+	}
+
+	a.pending = len(deps)
+	go a.updater.AnalyzeEach(deps, func(i int, analysis *models.UpdateAnalysis, err error) {
+		a.results <- result{index: i, analysis: analysis, err: err}
+	})
+}
+
+// wait blocks until the background analysis is complete
+func (a *App) wait() {
+	for a.pending > 0 {
+		a.finish(<-a.results)
+	}
+}
+
+// finish records the analysis of a dependency
+func (a *App) finish(res result) {
+	r := a.rows[res.index]
+	r.analysis, r.err = res.analysis, res.err
+
+	switch {
+	case res.err != nil:
+		r.status = statusError
+	case r.dep.UpdateNeeded && res.analysis.ShouldUpdate:
+		r.status = statusApproved
+	case r.dep.UpdateNeeded:
+		r.status = statusRejected
+	case r.dep.Pending != nil || r.dep.Ignored != nil:
+		r.status = statusHeld
+	default:
+		r.status = statusUpToDate
+	}
+
+	a.pending--
+	if a.pending == 0 {
+		a.message = fmt.Sprintf("Analysis complete: %d approved, %d rejected, %d errors",
+			a.count(statusApproved), a.count(statusRejected), a.count(statusError))
+	}
+	a.clampCursor()
+}
+
+// count returns the number of rows in a state
+func (a *App) count(s status) int {
+	n := 0
+	for _, r := range a.rows {
+		if r.status == s {
+			n++
+		}
+	}
+	return n
+}
+
+// visible returns the rows shown in the table: every dependency but the up-to-date ones
+func (a *App) visible() []*row {
+	rows := make([]*row, 0, len(a.rows))
+	for _, r := range a.rows {
+		if r.status != statusUpToDate {
+			rows = append(rows, r)
+		}
+	}
+	return rows
+}
+
+// current returns the row under the cursor, nil when the table is empty
+func (a *App) current() *row {
+	rows := a.visible()
+	if len(rows) == 0 {
+		return nil
+	}
+	return rows[a.cursor]
+}
+
+// selection returns the rows selected for applying
+func (a *App) selection() []*row {
+	var rows []*row
+	for _, r := range a.rows {
+		if r.selected {
+			rows = append(rows, r)
+		}
+	}
+	return rows
+}
+
+// handle reacts to a key and reports whether the UI should quit
+func (a *App) handle(event Event) bool {
+	if a.confirm {
+		a.confirm = false
+		if event.Key == KeyRune && (event.Rune == 'y' || event.Rune == 'Y') {
+			a.apply()
+		} else {
+			a.message = "Apply cancelled"
+		}
+		return false
+	}
+
+	if event.Key == KeyRune {
+		if action, ok := runeActions[event.Rune]; ok {
+			return action(a)
+		}
+		if tab := int(event.Rune - '1'); tab >= 0 && tab < len(detailTabs) {
+			a.tab = tab
+		}
+		return false
+	}
+
+	if action, ok := keyActions[event.Key]; ok {
+		return action(a)
+	}
+	return false
+}
+
+// keyActions maps the special keys to their actions, which report whether the UI should quit
+var keyActions = map[Key]func(a *App) bool{
+	KeyUp:       func(a *App) bool { a.move(-1); return false },
+	KeyDown:     func(a *App) bool { a.move(1); return false },
+	KeyPageUp:   func(a *App) bool { a.move(-a.pageSize()); return false },
+	KeyPageDown: func(a *App) bool { a.move(a.pageSize()); return false },
+	KeyHome:     func(a *App) bool { a.move(-len(a.rows)); return false },
+	KeyEnd:      func(a *App) bool { a.move(len(a.rows)); return false },
+	KeyTab:      func(a *App) bool { a.tab = (a.tab + 1) % len(detailTabs); return false },
+	KeyEnter:    (*App).askApply,
+	KeyWait:     func(a *App) bool { a.wait(); return false },
+	KeyEscape:   func(*App) bool { return true },
+	KeyCtrlC:    func(*App) bool { return true },
+}
+
+// runeActions maps the character keys to their actions, which report whether the UI should quit
+var runeActions = map[rune]func(a *App) bool{
+	'k': func(a *App) bool { a.move(-1); return false },
+	'j': func(a *App) bool { a.move(1); return false },
+	'g': func(a *App) bool { a.move(-len(a.rows)); return false },
+	'G': func(a *App) bool { a.move(len(a.rows)); return false },
+	' ': (*App).toggle,
+	'a': (*App).selectApproved,
+	'u': (*App).unselectAll,
+	'x': (*App).askApply,
+	'?': func(a *App) bool { a.help = !a.help; return false },
+	'q': func(*App) bool { return true },
+}
+
+// move moves the cursor by a number of rows
+func (a *App) move(delta int) {
+	a.cursor += delta
+	a.clampCursor()
+}
+
+// clampCursor keeps the cursor on a visible row
+func (a *App) clampCursor() {
+	a.cursor = max(0, min(a.cursor, len(a.visible())-1))
+}
+
+// pageSize returns the number of rows moved by page up and down
+func (a *App) pageSize() int {
+	_, height := a.screen.Size()
+	return max(1, tableHeight(height, len(a.visible()))-1)
+}
+
+// toggle selects or unselects the update under the cursor
+func (a *App) toggle() bool {
+	r := a.current()
+	switch {
+	case r == nil:
+	case r.updatable():
+		r.selected = !r.selected
+	default:
+		a.message = r.dep.Name + " has no update to apply"
+	}
+	return false
+}
+
+// selectApproved selects every approved update
+func (a *App) selectApproved() bool {
+	for _, r := range a.rows {
+		if r.status == statusApproved {
+			r.selected = true
+		}
+	}
+	return false
+}
+
+// unselectAll clears the selection
+func (a *App) unselectAll() bool {
+	for _, r := range a.rows {
+		r.selected = false
+	}
+	return false
+}
+
+// askApply asks for the confirmation to apply the selected updates
+func (a *App) askApply() bool {
+	selected := len(a.selection())
+	switch {
+	case a.pending > 0:
+		a.message = "Wait for the analysis to complete before applying updates"
+	case selected == 0:
+		a.message = "Select updates with space, or all approved ones with a"
+	default:
+		a.confirm = true
+		a.message = fmt.Sprintf("Apply %d selected updates? (y/n)", selected)
+	}
+	return false
+}
+
+// apply applies the selected updates in one session, which is rolled back
+// entirely when any of them fails
+func (a *App) apply() {
+	selected := a.selection()
+
+	session, err := a.updater.BeginSession()
+	if err != nil {
+		a.message = "Failed to start update session: " + err.Error()
+		return
+	}
+
+	for i, r := range selected {
+		a.message = fmt.Sprintf("Applying %d/%d: %s %s", i+1, len(selected), r.dep.Name, r.dep.LatestVersion)
+		_ = a.draw()
+
+		if err := session.Apply(r.dep); err != nil {
+			r.status, r.err = statusFailed, err
+			a.message = fmt.Sprintf("Update of %s failed, all changes were rolled back", r.dep.Name)
+			return
+		}
+	}
+
+	a.message = "Running go mod tidy..."
+	_ = a.draw()
+	if err := session.Tidy(); err != nil {
+		a.message = "go mod tidy failed, all changes were rolled back"
+		return
+	}
+	if err := session.Commit(); err != nil {
+		a.message = "Failed to save the undo journal: " + err.Error()
+		return
+	}
+
+	for _, r := range selected {
+		r.status, r.selected = statusApplied, false
+	}
+	a.message = fmt.Sprintf("Applied %d updates, revert them with gupdeps undo", len(selected))
+}
@@ -0,0 +1,367 @@
+package tui
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// fakeResult is the analysis outcome of a module
+type fakeResult struct {
+	latest  string // newer version, none when up to date
+	approve bool
+	err     error
+}
+
+// fakeUpdater analyzes the modules with canned results
+type fakeUpdater struct {
+	results  map[string]fakeResult
+	release  chan struct{} // when set, the analysis waits until it is closed
+	session  *fakeSession
+	sessions int
+}
+
+func (f *fakeUpdater) AnalyzeEach(deps []*models.Dependency, done func(i int, analysis *models.UpdateAnalysis, err error)) {
+	if f.release != nil {
+		<-f.release
+	}
+
+	for i, dep := range deps {
+		res := f.results[dep.Name]
+		if res.err != nil {
+			done(i, nil, res.err)
+			continue
+		}
+
+		analysis := &models.UpdateAnalysis{Dependency: dep, ShouldUpdate: res.approve}
+		if res.latest != "" {
+			dep.LatestVersion, dep.UpdateNeeded = res.latest, true
+		}
+		if res.approve {
+			analysis.UpdateReason = "1 fixes"
+		} else {
+			analysis.RejectionReason = "too risky"
+		}
+		done(i, analysis, nil)
+	}
+}
+
+func (f *fakeUpdater) BeginSession() (session, error) {
+	f.sessions++
+	return f.session, nil
+}
+
+// fakeSession records the updates applied
+type fakeSession struct {
+	failOn    string // module whose update fails
+	applied   []string
+	tidied    bool
+	committed bool
+}
+
+func (s *fakeSession) Apply(dep *models.Dependency) error {
+	if dep.Name == s.failOn {
+		return errors.New("go get failed")
+	}
+	s.applied = append(s.applied, dep.Name)
+	return nil
+}
+
+func (s *fakeSession) Tidy() error {
+	s.tidied = true
+	return nil
+}
+
+func (s *fakeSession) Commit() error {
+	s.committed = true
+	return nil
+}
+
+// newFakeUpdater returns an updater approving a, rejecting b, failing on c
+// and finding d up to date
+func newFakeUpdater() *fakeUpdater {
+	return &fakeUpdater{
+		results: map[string]fakeResult{
+			"example.com/a": {latest: "v1.1.0", approve: true},
+			"example.com/b": {latest: "v2.0.0"},
+			"example.com/c": {err: errors.New("boom")},
+			"example.com/d": {approve: true},
+		},
+		session: &fakeSession{},
+	}
+}
+
+// run replays a script on the UI of four dependencies and returns the last frame
+func run(t *testing.T, f *fakeUpdater, script string) string {
+	t.Helper()
+
+	deps := []*models.Dependency{
+		{Name: "example.com/a", CurrentVersion: "v1.0.0"},
+		{Name: "example.com/b", CurrentVersion: "v1.0.0"},
+		{Name: "example.com/c", CurrentVersion: "v1.0.0"},
+		{Name: "example.com/d", CurrentVersion: "v1.0.0"},
+	}
+
+	events, err := NewScriptedEvents(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	screen := NewHeadless(100, 30)
+	if err := newApp(f, "/project", deps).Run(events, screen); err != nil {
+		t.Fatal(err)
+	}
+	return screen.String()
+}
+
+// tableLine returns the line of the table showing a module
+func tableLine(t *testing.T, frame, module string) string {
+	t.Helper()
+
+	for _, line := range strings.Split(frame, "\n") {
+		if len(line) > colSelect && strings.HasPrefix(line[colSelect:], module+" ") {
+			return line
+		}
+	}
+	t.Fatalf("no table line for %s in\n%s", module, frame)
+	return ""
+}
+
+// cursorModule returns the module of the table line under the cursor
+func cursorModule(t *testing.T, frame string) string {
+	t.Helper()
+
+	for _, line := range strings.Split(frame, "\n") {
+		if strings.HasPrefix(line, "> ") {
+			return strings.Fields(line[colSelect:])[0]
+		}
+	}
+	t.Fatalf("no cursor in\n%s", frame)
+	return ""
+}
+
+func TestNavigation(t *testing.T) {
+	tests := []struct {
+		script string
+		want   string
+	}{
+		{script: "<wait>", want: "example.com/a"},
+		{script: "<wait> j", want: "example.com/b"},
+		{script: "<wait> <down> <down>", want: "example.com/c"},
+		{script: "<wait> jjj", want: "example.com/c"},
+		{script: "<wait> jj k", want: "example.com/b"},
+		{script: "<wait> <up>", want: "example.com/a"},
+		{script: "<wait> G", want: "example.com/c"},
+		{script: "<wait> <end> <home>", want: "example.com/a"},
+		{script: "<wait> <end> g", want: "example.com/a"},
+		{script: "<wait> <pgdn>", want: "example.com/c"},
+		{script: "<wait> G <pgup>", want: "example.com/a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.script, func(t *testing.T) {
+			if got := cursorModule(t, run(t, newFakeUpdater(), tt.script)); got != tt.want {
+				t.Errorf("cursor on %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWait(t *testing.T) {
+	frame := run(t, newFakeUpdater(), "<wait>")
+
+	for module, status := range map[string]string{
+		"example.com/a": "approved",
+		"example.com/b": "rejected",
+		"example.com/c": "error",
+	} {
+		if line := tableLine(t, frame, module); !strings.HasSuffix(line, status) {
+			t.Errorf("line of %s = %q, want status %s", module, line, status)
+		}
+	}
+
+	if !strings.Contains(tableLine(t, frame, "example.com/a"), "v1.1.0") {
+		t.Error("the target version of a must be shown")
+	}
+	if strings.Contains(frame, "example.com/d") {
+		t.Error("up-to-date dependencies must be hidden")
+	}
+	if !strings.Contains(frame, "4 dependencies, 3 shown") {
+		t.Errorf("title must count the shown dependencies:\n%s", frame)
+	}
+	if !strings.Contains(frame, "Analysis complete: 1 approved, 1 rejected, 1 errors") {
+		t.Errorf("status bar must report the end of the analysis:\n%s", frame)
+	}
+}
+
+func TestDetails(t *testing.T) {
+	tests := []struct {
+		script string
+		want   []string
+	}{
+		{script: "<wait>", want: []string{"[1 Commits]", "Approved: 1 fixes", "No commits found"}},
+		{script: "<wait> <tab>", want: []string{"[2 Changelog]", "No changelog or release notes found"}},
+		{script: "<wait> 3", want: []string{"[3 API]", "API changes were not analyzed"}},
+		{script: "<wait> j 4", want: []string{"[4 Risk]", "Rejected: too risky", "No risk assessment"}},
+		{script: "<wait> jj", want: []string{"Error: boom"}},
+		{script: "<wait> ?", want: []string{"Keys", "show or hide this help"}},
+		{script: "<wait> ? ?", want: []string{"[1 Commits]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.script, func(t *testing.T) {
+			frame := run(t, newFakeUpdater(), tt.script)
+			for _, want := range tt.want {
+				if !strings.Contains(frame, want) {
+					t.Errorf("frame lacks %q:\n%s", want, frame)
+				}
+			}
+		})
+	}
+}
+
+func TestSelection(t *testing.T) {
+	frame := run(t, newFakeUpdater(), "<wait> <space> j <space> j <space>")
+	if !strings.Contains(frame, "2 selected") {
+		t.Errorf("approved and rejected updates must be selectable:\n%s", frame)
+	}
+	if !strings.Contains(frame, "example.com/c has no update to apply") {
+		t.Errorf("failed analyses must not be selectable:\n%s", frame)
+	}
+
+	frame = run(t, newFakeUpdater(), "<wait> a")
+	if !strings.HasPrefix(tableLine(t, frame, "example.com/a"), "> [x]") ||
+		!strings.HasPrefix(tableLine(t, frame, "example.com/b"), "  [ ]") {
+		t.Errorf("a must select the approved updates only:\n%s", frame)
+	}
+
+	frame = run(t, newFakeUpdater(), "<wait> a u")
+	if !strings.Contains(frame, "0 selected") {
+		t.Errorf("u must clear the selection:\n%s", frame)
+	}
+}
+
+func TestApply(t *testing.T) {
+	f := newFakeUpdater()
+	frame := run(t, f, "<wait> a j <space> x y")
+
+	if want := []string{"example.com/a", "example.com/b"}; !slices.Equal(f.session.applied, want) {
+		t.Errorf("applied %v, want %v", f.session.applied, want)
+	}
+	if !f.session.tidied || !f.session.committed {
+		t.Error("the session must be tidied and committed")
+	}
+	for _, module := range []string{"example.com/a", "example.com/b"} {
+		if line := tableLine(t, frame, module); !strings.HasSuffix(line, "applied") {
+			t.Errorf("line of %s = %q, want status applied", module, line)
+		}
+	}
+	if !strings.Contains(frame, "Applied 2 updates, revert them with gupdeps undo") {
+		t.Errorf("status bar must report the applied updates:\n%s", frame)
+	}
+}
+
+func TestApplyConfirmation(t *testing.T) {
+	f := newFakeUpdater()
+	frame := run(t, f, "<wait> a <enter>")
+	if !strings.Contains(frame, "Apply 1 selected updates? (y/n)") {
+		t.Errorf("apply must ask for a confirmation:\n%s", frame)
+	}
+
+	frame = run(t, f, "<wait> a x n")
+	if f.sessions != 0 {
+		t.Error("a cancelled apply must not start a session")
+	}
+	if !strings.Contains(frame, "Apply cancelled") || !strings.HasPrefix(tableLine(t, frame, "example.com/a"), "> [x]") {
+		t.Errorf("a cancelled apply must keep the selection:\n%s", frame)
+	}
+
+	frame = run(t, f, "<wait> x")
+	if !strings.Contains(frame, "Select updates with space") {
+		t.Errorf("apply without a selection must explain how to select:\n%s", frame)
+	}
+}
+
+func TestApplyFailure(t *testing.T) {
+	f := newFakeUpdater()
+	f.session.failOn = "example.com/b"
+	frame := run(t, f, "<wait> a j <space> x y")
+
+	if f.session.committed {
+		t.Error("a failed session must not be committed")
+	}
+	if line := tableLine(t, frame, "example.com/b"); !strings.HasSuffix(line, "failed") {
+		t.Errorf("line of b = %q, want status failed", line)
+	}
+	if !strings.Contains(frame, "Update of example.com/b failed, all changes were rolled back") {
+		t.Errorf("status bar must report the rollback:\n%s", frame)
+	}
+}
+
+func TestWhileAnalyzing(t *testing.T) {
+	f := newFakeUpdater()
+	f.release = make(chan struct{})
+	t.Cleanup(func() { close(f.release) })
+
+	frame := run(t, f, "j x q j")
+
+	if line := tableLine(t, frame, "example.com/a"); !strings.HasSuffix(line, "analyzing") {
+		t.Errorf("line of a = %q, want status analyzing", line)
+	}
+	if !strings.Contains(frame, "Analyzing 0/4 dependencies") {
+		t.Errorf("status bar must show the progress:\n%s", frame)
+	}
+	if !strings.Contains(frame, "Wait for the analysis") {
+		t.Errorf("apply must wait for the analysis:\n%s", frame)
+	}
+	if got := cursorModule(t, frame); got != "example.com/b" {
+		t.Errorf("cursor on %s, keys after q must be ignored", got)
+	}
+}
+
+func TestQuitKeys(t *testing.T) {
+	for _, script := range []string{"q j", "<esc> j", "<ctrl-c> j"} {
+		f := newFakeUpdater()
+		f.release = make(chan struct{})
+
+		if got := cursorModule(t, run(t, f, script)); got != "example.com/a" {
+			t.Errorf("%s: cursor on %s, keys after quitting must be ignored", script, got)
+		}
+		close(f.release)
+	}
+}
+
+func TestScriptedEvents(t *testing.T) {
+	if _, err := NewScriptedEvents("<wait> <bogus>"); err == nil {
+		t.Error("unknown named keys must be rejected")
+	}
+
+	events, err := NewScriptedEvents("<WAIT> jk <space>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Event{{Key: KeyWait}, {Key: KeyRune, Rune: 'j'}, {Key: KeyRune, Rune: 'k'}, {Key: KeyRune, Rune: ' '}}
+	for i := range want {
+		if event, err := events.Next(); err != nil || event != want[i] {
+			t.Errorf("event %d = %v, %v, want %v", i, event, err, want[i])
+		}
+	}
+	if _, err := events.Next(); err == nil {
+		t.Error("the end of the script must return an error")
+	}
+}
+
+func TestTerminalEvents(t *testing.T) {
+	events := NewTerminalEvents(strings.NewReader("j\x1b[A\x1b[6~\r\t\x03"))
+
+	want := []Event{{Key: KeyRune, Rune: 'j'}, {Key: KeyUp}, {Key: KeyPageDown}, {Key: KeyEnter}, {Key: KeyTab}, {Key: KeyCtrlC}}
+	for i := range want {
+		if event, err := events.Next(); err != nil || event != want[i] {
+			t.Errorf("event %d = %v, %v, want %v", i, event, err, want[i])
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Key identifies a key, or a control event of scripted runs
+type Key int
+
+// Keys understood by the UI
+const (
+	KeyRune Key = iota // a character, in Event.Rune
+	KeyUp
+	KeyDown
+	KeyPageUp
+	KeyPageDown
+	KeyHome
+	KeyEnd
+	KeyEnter
+	KeyTab
+	KeyEscape
+	KeyCtrlC
+	KeyWait // waits until the background analysis is complete, scripted runs only
+)
+
+// Event is a key press
+type Event struct {
+	Key  Key
+	Rune rune
+}
+
+// EventSource delivers the key events the UI reacts to. Next blocks until an
+// event is available and returns io.EOF when there are no more.
+type EventSource interface {
+	Next() (Event, error)
+}
+
+// terminalEvents decodes the key presses of a terminal in raw mode
+type terminalEvents struct {
+	reader *bufio.Reader
+}
+
+// NewTerminalEvents returns the key presses read from a terminal in raw mode
+func NewTerminalEvents(r io.Reader) EventSource {
+	return &terminalEvents{reader: bufio.NewReader(r)}
+}
+
+// Next decodes the next key press, skipping unknown escape sequences
+func (t *terminalEvents) Next() (Event, error) {
+	for {
+		r, _, err := t.reader.ReadRune()
+		if err != nil {
+			return Event{}, err
+		}
+
+		switch r {
+		case '\x1b':
+			if event, ok := t.escapeSequence(); ok {
+				return event, nil
+			}
+		case '\r', '\n':
+			return Event{Key: KeyEnter}, nil
+		case '\t':
+			return Event{Key: KeyTab}, nil
+		case '\x03':
+			return Event{Key: KeyCtrlC}, nil
+		default:
+			return Event{Key: KeyRune, Rune: r}, nil
+		}
+	}
+}
+
+// csiKeys maps the final part of CSI and SS3 sequences to keys
+var csiKeys = map[string]Key{
+	"A":  KeyUp,
+	"B":  KeyDown,
+	"H":  KeyHome,
+	"F":  KeyEnd,
+	"1~": KeyHome,
+	"4~": KeyEnd,
+	"5~": KeyPageUp,
+	"6~": KeyPageDown,
+}
+
+// escapeSequence decodes the rest of an escape sequence. A lone escape, with
+// nothing following it in the same read, is the escape key.
+func (t *terminalEvents) escapeSequence() (Event, bool) {
+	if t.reader.Buffered() == 0 {
+		return Event{Key: KeyEscape}, true
+	}
+
+	introducer, _ := t.reader.ReadByte()
+	if introducer != '[' && introducer != 'O' {
+		return Event{}, false
+	}
+
+	var sequence strings.Builder
+	for t.reader.Buffered() > 0 {
+		b, _ := t.reader.ReadByte()
+		sequence.WriteByte(b)
+		if b >= 0x40 && b <= 0x7e { // final byte
+			break
+		}
+	}
+
+	key, ok := csiKeys[sequence.String()]
+	return Event{Key: key}, ok
+}
+
+// namedKeys maps the key names of scripts to keys
+var namedKeys = map[string]Event{
+	"<up>":     {Key: KeyUp},
+	"<down>":   {Key: KeyDown},
+	"<pgup>":   {Key: KeyPageUp},
+	"<pgdn>":   {Key: KeyPageDown},
+	"<home>":   {Key: KeyHome},
+	"<end>":    {Key: KeyEnd},
+	"<enter>":  {Key: KeyEnter},
+	"<tab>":    {Key: KeyTab},
+	"<esc>":    {Key: KeyEscape},
+	"<space>":  {Key: KeyRune, Rune: ' '},
+	"<ctrl-c>": {Key: KeyCtrlC},
+	"<wait>":   {Key: KeyWait},
+}
+
+// scriptedEvents replays a fixed list of events
+type scriptedEvents struct {
+	events []Event
+}
+
+// NewScriptedEvents parses a script of whitespace-separated keys: named keys
+// such as <down>, <space> or <wait>, and characters, every character of a
+// word being a key press. "<wait> jj <space> x y" waits for the analysis,
+// moves down twice, selects the update and applies it.
+func NewScriptedEvents(script string) (EventSource, error) {
+	var events []Event
+	for _, word := range strings.Fields(script) {
+		if strings.HasPrefix(word, "<") && strings.HasSuffix(word, ">") {
+			event, ok := namedKeys[strings.ToLower(word)]
+			if !ok {
+				return nil, fmt.Errorf("unknown key %s in script", word)
+			}
+			events = append(events, event)
+			continue
+		}
+
+		for _, r := range word {
+			events = append(events, Event{Key: KeyRune, Rune: r})
+		}
+	}
+
+	return &scriptedEvents{events: events}, nil
+}
+
+// Next returns the next event of the script
+func (s *scriptedEvents) Next() (Event, error) {
+	if len(s.events) == 0 {
+		return Event{}, io.EOF
+	}
+
+	event := s.events[0]
+	s.events = s.events[1:]
+	return event, nil
+}
@@ -0,0 +1,142 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Style is the appearance of a line on the screen
+type Style int
+
+// Line styles
+const (
+	StylePlain Style = iota
+	StyleTitle
+	StyleHeader
+	StyleCursor
+	StyleStatus
+)
+
+// Line is a line of a frame, padded to the width of the screen
+type Line struct {
+	Text  string
+	Style Style
+}
+
+// Screen displays the frames of the UI
+type Screen interface {
+	Size() (width, height int)
+	Draw(frame []Line) error
+}
+
+// ansiStyles holds the escape sequences of the line styles
+var ansiStyles = map[Style]string{
+	StyleTitle:  "\x1b[1m",
+	StyleHeader: "\x1b[1;4m",
+	StyleCursor: "\x1b[7m",
+	StyleStatus: "\x1b[30;46m",
+}
+
+// Terminal is a Screen on the controlling terminal, in raw mode and on the
+// alternate screen buffer until it is closed
+type Terminal struct {
+	in    *os.File
+	out   *os.File
+	state *term.State
+}
+
+// OpenTerminal switches the terminal to raw mode and the alternate screen
+func OpenTerminal() (*Terminal, error) {
+	in, out := os.Stdin, os.Stdout
+	if !term.IsTerminal(int(in.Fd())) || !term.IsTerminal(int(out.Fd())) {
+		return nil, errors.New("standard input and output must be a terminal")
+	}
+
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to switch the terminal to raw mode: %w", err)
+	}
+
+	// Alternate screen, hidden cursor
+	if _, err := out.WriteString("\x1b[?1049h\x1b[?25l"); err != nil {
+		_ = term.Restore(int(in.Fd()), state)
+		return nil, fmt.Errorf("failed to set up the terminal: %w", err)
+	}
+
+	return &Terminal{in: in, out: out, state: state}, nil
+}
+
+// Events returns the key presses of the terminal
+func (t *Terminal) Events() EventSource {
+	return NewTerminalEvents(t.in)
+}
+
+// Close restores the terminal to the state it was opened in
+func (t *Terminal) Close() error {
+	_, _ = t.out.WriteString("\x1b[?25h\x1b[?1049l")
+	return term.Restore(int(t.in.Fd()), t.state)
+}
+
+// Size returns the size of the terminal, 80x24 when it cannot be determined
+func (t *Terminal) Size() (width, height int) {
+	width, height, err := term.GetSize(int(t.out.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+// Draw replaces the screen contents with a frame
+func (t *Terminal) Draw(frame []Line) error {
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for i, line := range frame {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(ansiStyles[line.Style])
+		b.WriteString(line.Text)
+		b.WriteString("\x1b[0m\x1b[K")
+	}
+	b.WriteString("\x1b[J")
+
+	_, err := t.out.WriteString(b.String())
+	return err
+}
+
+// Headless is a Screen of fixed size keeping the last frame drawn, for
+// scripted runs without a terminal
+type Headless struct {
+	width  int
+	height int
+	frame  []Line
+}
+
+// NewHeadless creates a headless screen of the given size
+func NewHeadless(width, height int) *Headless {
+	return &Headless{width: width, height: height}
+}
+
+// Size returns the size of the screen
+func (h *Headless) Size() (width, height int) {
+	return h.width, h.height
+}
+
+// Draw keeps the frame
+func (h *Headless) Draw(frame []Line) error {
+	h.frame = append(h.frame[:0], frame...)
+	return nil
+}
+
+// String returns the text of the last frame, without styles
+func (h *Headless) String() string {
+	lines := make([]string, len(h.frame))
+	for i, line := range h.frame {
+		lines[i] = strings.TrimRight(line.Text, " ")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
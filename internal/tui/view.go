@@ -0,0 +1,354 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/moeryomenko/gupdeps/internal/dependencies"
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// detailTabs lists the tabs of the detail pane
+var detailTabs = []string{"Commits", "Changelog", "API", "Risk"}
+
+// spinnerFrames animate the status bar while the analysis runs
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// helpLines describe the keys, shown in place of the detail pane
+var helpLines = []string{
+	"up/k, down/j     move the cursor",
+	"pgup, pgdn, g/G  move by a page, to the first or last row",
+	"space            select or unselect the update under the cursor",
+	"a, u             select all approved updates, unselect all",
+	"x, enter         apply the selected updates",
+	"tab, 1-4         switch the tab of the detail pane",
+	"?                show or hide this help",
+	"q, esc           quit",
+}
+
+// Widths of the table columns; the module column takes the remaining width
+const (
+	colSelect      = 6
+	colVersion     = 12
+	colBump        = 11
+	colRisk        = 13
+	colStatus      = 11
+	minModuleWidth = 20
+)
+
+// tableHeight returns the number of rows of the table, which gets at most
+// half of the lines left by the title, header, tab bar, separator and status bar
+func tableHeight(height, rows int) int {
+	return max(1, min(rows, (height-5)/2))
+}
+
+// render returns the frame of the current state for a screen of the given size
+func (a *App) render(width, height int) []Line {
+	rows := a.visible()
+	visibleRows := tableHeight(height, len(rows))
+	a.scroll(visibleRows, len(rows))
+
+	frame := make([]Line, 0, height)
+	title := fmt.Sprintf("gupdeps  %s  %d dependencies, %d shown", a.project, len(a.rows), len(rows))
+	frame = append(frame,
+		Line{Text: fit(title, width), Style: StyleTitle},
+		Line{Text: tableRow(width, "", "Module", "Current", "Target", "Bump", "Risk", "Status"), Style: StyleHeader},
+	)
+
+	for i := a.offset; i < a.offset+visibleRows; i++ {
+		switch {
+		case i < len(rows):
+			line := Line{Text: a.formatRow(rows[i], i == a.cursor, width)}
+			if i == a.cursor {
+				line.Style = StyleCursor
+			}
+			frame = append(frame, line)
+		case a.pending > 0:
+			frame = append(frame, Line{Text: fit("  Analyzing dependencies...", width)})
+		default:
+			frame = append(frame, Line{Text: fit("  All dependencies are up to date", width)})
+		}
+	}
+
+	frame = append(frame, Line{Text: fit("", width)}, Line{Text: a.tabBar(width), Style: StyleHeader})
+
+	details := helpLines
+	if !a.help {
+		details = a.details(a.current())
+	}
+	for i := 0; len(frame) < height-1; i++ {
+		text := ""
+		if i < len(details) {
+			text = "  " + details[i]
+		}
+		frame = append(frame, Line{Text: fit(text, width)})
+	}
+
+	return append(frame, Line{Text: a.statusBar(width), Style: StyleStatus})
+}
+
+// scroll keeps the cursor within the rows shown in the table
+func (a *App) scroll(visibleRows, rows int) {
+	if a.cursor < a.offset {
+		a.offset = a.cursor
+	}
+	if a.cursor >= a.offset+visibleRows {
+		a.offset = a.cursor - visibleRows + 1
+	}
+	a.offset = max(0, min(a.offset, rows-visibleRows))
+}
+
+// formatRow returns the table line of a dependency
+func (a *App) formatRow(r *row, cursor bool, width int) string {
+	marker := "  "
+	if cursor {
+		marker = "> "
+	}
+	switch {
+	case r.selected:
+		marker += "[x]"
+	case r.updatable():
+		marker += "[ ]"
+	}
+
+	var target, bump, risk string
+	if r.status != statusQueued {
+		// The analysis is done, so the dependency is no longer written concurrently
+		target, bump = updateTarget(r.dep)
+		if r.analysis != nil && r.analysis.Risk != nil {
+			risk = fmt.Sprintf("%d %s", r.analysis.Risk.Score, r.analysis.Risk.Level)
+		}
+	}
+
+	status := statusNames[r.status]
+	if r.status == statusQueued {
+		status = spinnerFrames[a.spinner%len(spinnerFrames)] + " " + status
+	}
+
+	return tableRow(width, marker, r.dep.Name, r.dep.CurrentVersion, target, bump, risk, status)
+}
+
+// updateTarget returns the version a dependency would be updated to and the
+// size of the change, or the held back version in parentheses
+func updateTarget(dep *models.Dependency) (target, bump string) {
+	switch {
+	case dep.UpdateNeeded:
+		return dep.LatestVersion, dependencies.BumpKind(dep.CurrentVersion, dep.LatestVersion)
+	case dep.Pending != nil:
+		return "(" + dep.Pending.Version + ")", "pending"
+	case dep.Ignored != nil:
+		return "(" + dep.Ignored.Version + ")", "ignored"
+	default:
+		return "", ""
+	}
+}
+
+// tableRow lays out the cells of a table line
+func tableRow(width int, marker, module, current, target, bump, risk, status string) string {
+	moduleWidth := max(minModuleWidth, width-colSelect-2*colVersion-colBump-colRisk-colStatus)
+	line := fit(marker, colSelect) +
+		fit(module, moduleWidth-1) + " " +
+		fit(current, colVersion) +
+		fit(target, colVersion) +
+		fit(bump, colBump) +
+		fit(risk, colRisk) +
+		fit(status, colStatus)
+	return fit(line, width)
+}
+
+// tabBar returns the line naming the tabs of the detail pane, the current one in brackets
+func (a *App) tabBar(width int) string {
+	if a.help {
+		return fit("Keys", width)
+	}
+
+	tabs := make([]string, len(detailTabs))
+	for i, name := range detailTabs {
+		if i == a.tab {
+			tabs[i] = fmt.Sprintf("[%d %s]", i+1, name)
+		} else {
+			tabs[i] = fmt.Sprintf(" %d %s ", i+1, name)
+		}
+	}
+	return fit(strings.Join(tabs, " "), width)
+}
+
+// statusBar returns the bottom line: the analysis progress, the last message
+// and the selection
+func (a *App) statusBar(width int) string {
+	left := a.message
+	if a.pending > 0 {
+		left = fmt.Sprintf("%s Analyzing %d/%d dependencies",
+			spinnerFrames[a.spinner%len(spinnerFrames)], len(a.rows)-a.pending, len(a.rows))
+		if a.message != "" {
+			left += " | " + a.message
+		}
+	}
+
+	right := fmt.Sprintf("%d selected | ? help | q quit ", len(a.selection()))
+	return fit(" "+left, max(0, width-len(right))) + right
+}
+
+// details returns the lines of the detail pane for a row
+func (a *App) details(r *row) []string {
+	if r == nil {
+		return nil
+	}
+
+	lines := []string{r.dep.Name + " " + r.dep.CurrentVersion}
+	switch r.status {
+	case statusQueued:
+		return append(lines, "Analyzing...")
+	case statusError, statusFailed:
+		return append(lines, "Error: "+firstLine(r.err.Error()))
+	case statusHeld:
+		return append(lines, heldLines(r.dep)...)
+	}
+
+	lines[0] += " -> " + r.dep.LatestVersion
+	if r.analysis.ShouldUpdate {
+		lines = append(lines, "Approved: "+r.analysis.UpdateReason)
+	} else {
+		lines = append(lines, "Rejected: "+r.analysis.RejectionReason)
+	}
+	lines = append(lines, heldLines(r.dep)...)
+	if len(r.dep.Retracted) > 0 {
+		lines = append(lines, r.dep.CurrentVersion+" is retracted: "+strings.Join(r.dep.Retracted, "; "))
+	}
+	lines = append(lines, "")
+
+	switch detailTabs[a.tab] {
+	case "Commits":
+		return append(lines, commitLines(r.analysis.Commits)...)
+	case "Changelog":
+		return append(lines, changelogLines(r.analysis.Changelog)...)
+	case "API":
+		return append(lines, apiLines(r.analysis.APIDiff)...)
+	default:
+		return append(lines, riskLines(r.analysis)...)
+	}
+}
+
+// heldLines describes the releases of a dependency held back by the minimum
+// release age or the ignore list
+func heldLines(dep *models.Dependency) []string {
+	var lines []string
+	if dep.Pending != nil {
+		lines = append(lines, dep.Pending.Version+" pending until "+dep.Pending.Until.Format(time.DateOnly))
+	}
+	if dep.Ignored != nil {
+		lines = append(lines, dep.Ignored.Version+" ignored: "+dep.Ignored.Reason)
+	}
+	return lines
+}
+
+// commitLines lists the commits of an update
+func commitLines(commits []models.CommitInfo) []string {
+	if len(commits) == 0 {
+		return []string{"No commits found"}
+	}
+
+	lines := make([]string, 0, len(commits))
+	for i := range commits {
+		commit := &commits[i]
+		subject := firstLine(commit.Message)
+		if commit.Category != "" {
+			subject = "[" + commit.Category + "] " + subject
+		}
+		lines = append(lines, fmt.Sprintf("%s %s %s",
+			commit.Hash[:min(7, len(commit.Hash))], commit.Date.Format(time.DateOnly), subject))
+	}
+	return lines
+}
+
+// changelogLines lists the changelog entries and release notes of an update
+func changelogLines(changelog *models.Changelog) []string {
+	if changelog == nil || (len(changelog.Sections) == 0 && len(changelog.TagNotes) == 0) {
+		return []string{"No changelog or release notes found"}
+	}
+
+	var lines []string
+	for _, section := range changelog.Sections {
+		lines = append(lines, strings.TrimSpace(section.Version+" "+section.Date))
+		for _, group := range models.ChangelogGroups {
+			for _, entry := range section.Entries[group] {
+				lines = append(lines, fmt.Sprintf("  %-10s %s", group, firstLine(entry)))
+			}
+		}
+	}
+	for _, note := range changelog.TagNotes {
+		lines = append(lines, "Release notes of "+note.Tag+":")
+		for _, line := range strings.Split(note.Message, "\n") {
+			lines = append(lines, "  "+line)
+		}
+	}
+	return lines
+}
+
+// apiLines lists the API changes of an update
+func apiLines(diff *models.APIDiff) []string {
+	if diff == nil {
+		return []string{"API changes were not analyzed"}
+	}
+	if len(diff.Incompatible) == 0 && len(diff.Compatible) == 0 {
+		return []string{"No API changes"}
+	}
+
+	lines := make([]string, 0, len(diff.Incompatible)+len(diff.Compatible))
+	for _, change := range diff.Incompatible {
+		lines = append(lines, "- "+change)
+	}
+	for _, change := range diff.Compatible {
+		lines = append(lines, "+ "+change)
+	}
+	return lines
+}
+
+// riskLines lists the risk factors of an update with the findings that feed the decision
+func riskLines(analysis *models.UpdateAnalysis) []string {
+	var lines []string
+	if risk := analysis.Risk; risk != nil {
+		lines = append(lines, fmt.Sprintf("Risk: %d (%s)", risk.Score, risk.Level))
+		for _, factor := range risk.Factors {
+			lines = append(lines, fmt.Sprintf("  %+3d %-18s %s", factor.Points, factor.Name, factor.Detail))
+		}
+	}
+	if license := analysis.License; license != nil {
+		lines = append(lines, "License: "+dependencies.LicenseName(license.From)+" -> "+dependencies.LicenseName(license.To))
+	}
+	if requirement := analysis.GoRequirement; requirement != nil && requirement.To != "" {
+		lines = append(lines, "Go: "+requirement.From+" -> "+requirement.To)
+	}
+	if analysis.SizeImpact != nil {
+		lines = append(lines, "Binary size: "+dependencies.FormatSizeImpact(analysis.SizeImpact))
+	}
+	for i := range analysis.Vulnerabilities {
+		vuln := &analysis.Vulnerabilities[i]
+		lines = append(lines, fmt.Sprintf("Vulnerability %s: %s", vuln.ID, vuln.Summary))
+	}
+	if len(lines) == 0 {
+		return []string{"No risk assessment"}
+	}
+	return lines
+}
+
+// firstLine returns the first line of a text
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
+
+// fit truncates or pads a text to a width in runes
+func fit(text string, width int) string {
+	text = strings.ReplaceAll(text, "\t", " ")
+	runes := []rune(text)
+	switch {
+	case len(runes) > width && width > 1:
+		return string(runes[:width-1]) + "…"
+	case len(runes) > width:
+		return string(runes[:width])
+	default:
+		return text + strings.Repeat(" ", width-len(runes))
+	}
+}
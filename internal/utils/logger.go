@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 )
@@ -12,6 +13,7 @@ type Logger struct {
 	warn    *log.Logger
 	error   *log.Logger
 	success *log.Logger
+	out     io.Writer
 	verbose bool
 }
 
@@ -22,10 +24,19 @@ func NewLogger(verbose bool) *Logger {
 		warn:    log.New(os.Stdout, "⚠️ WARN: ", log.LstdFlags),
 		error:   log.New(os.Stderr, "❌ ERROR: ", log.LstdFlags),
 		success: log.New(os.Stdout, "✅ SUCCESS: ", log.LstdFlags),
+		out:     os.Stdout,
 		verbose: verbose,
 	}
 }
 
+// SetOutput redirects everything but errors, which always go to stderr
+func (l *Logger) SetOutput(w io.Writer) {
+	l.info.SetOutput(w)
+	l.warn.SetOutput(w)
+	l.success.SetOutput(w)
+	l.out = w
+}
+
 // Info logs informational messages
 func (l *Logger) Info(format string, v ...any) {
 	if l.verbose {
@@ -48,7 +59,7 @@ func (l *Logger) Success(format string, v ...any) {
 	l.success.Printf(format, v...)
 }
 
-// Print outputs a message to stdout without any prefix
+// Print outputs a message without any prefix
 func (l *Logger) Print(format string, v ...any) {
-	fmt.Printf(format+"\n", v...)
+	fmt.Fprintf(l.out, format+"\n", v...)
 }
@@ -0,0 +1,251 @@
+// Package vulndb reads a local copy of the Go vulnerability database in the
+// vuln.go.dev layout: index/modules.json plus one OSV entry per ID/<id>.json.
+package vulndb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/moeryomenko/gupdeps/internal/models"
+)
+
+// EnvVar is the environment variable pointing at the vulnerability database
+const EnvVar = "GOVULNDB"
+
+// DB is a read-only view of a local vulnerability database
+type DB struct {
+	dir     string
+	modules map[string][]string // module path -> advisory IDs
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+// entry is the subset of an OSV entry used by gupdeps
+type entry struct {
+	ID       string   `json:"id"`
+	Summary  string   `json:"summary"`
+	Aliases  []string `json:"aliases"`
+	Affected []struct {
+		Package struct {
+			Name string `json:"name"`
+		} `json:"package"`
+		Ranges            []osvRange `json:"ranges"`
+		EcosystemSpecific struct {
+			Imports []struct {
+				Path    string   `json:"path"`
+				Symbols []string `json:"symbols"`
+			} `json:"imports"`
+		} `json:"ecosystem_specific"`
+	} `json:"affected"`
+}
+
+// osvRange is an OSV range of affected versions
+type osvRange struct {
+	Type   string `json:"type"`
+	Events []struct {
+		Introduced string `json:"introduced,omitempty"`
+		Fixed      string `json:"fixed,omitempty"`
+	} `json:"events"`
+}
+
+// Locate returns the database directory from a flag value or, when empty, from
+// GOVULNDB. Only file:// URLs are supported; an empty result disables the check.
+func Locate(flagValue string) (string, error) {
+	location := flagValue
+	if location == "" {
+		location = os.Getenv(EnvVar)
+	}
+
+	if location == "" || !strings.Contains(location, "://") {
+		return location, nil
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return "", fmt.Errorf("invalid vulnerability database URL %q: %w", location, err)
+	}
+
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("vulnerability database %q is not local, only file:// is supported", location)
+	}
+
+	return filepath.FromSlash(u.Path), nil
+}
+
+// Open loads the module index of a local vulnerability database
+func Open(dir string) (*DB, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "index", "modules.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vulnerability database index: %w", err)
+	}
+
+	var index []struct {
+		Path  string `json:"path"`
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to decode vulnerability database index: %w", err)
+	}
+
+	db := &DB{
+		dir:     dir,
+		modules: make(map[string][]string, len(index)),
+		entries: make(map[string]*entry),
+	}
+	for _, module := range index {
+		for _, vuln := range module.Vulns {
+			db.modules[module.Path] = append(db.modules[module.Path], vuln.ID)
+		}
+	}
+
+	return db, nil
+}
+
+// Vulnerabilities returns the advisories affecting a module version. FixedByUpdate
+// is set for advisories that do not affect the target version.
+func (db *DB) Vulnerabilities(module, version, target string) ([]models.Vulnerability, error) {
+	var vulns []models.Vulnerability
+
+	for _, id := range db.modules[module] {
+		e, err := db.load(id)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range e.Affected {
+			affected := &e.Affected[i]
+			if affected.Package.Name != module {
+				continue
+			}
+
+			ranges := semverRanges(affected.Ranges)
+			if !isAffected(ranges, version) {
+				continue
+			}
+
+			vuln := models.Vulnerability{
+				ID:            e.ID,
+				Aliases:       e.Aliases,
+				Summary:       e.Summary,
+				FixedIn:       fixedIn(ranges, version),
+				FixedByUpdate: target != "" && !isAffected(ranges, target),
+			}
+			for _, imp := range affected.EcosystemSpecific.Imports {
+				vuln.Packages = append(vuln.Packages, models.VulnerablePackage{Path: imp.Path, Symbols: imp.Symbols})
+			}
+
+			vulns = append(vulns, vuln)
+			break
+		}
+	}
+
+	sort.Slice(vulns, func(i, j int) bool { return vulns[i].ID < vulns[j].ID })
+	return vulns, nil
+}
+
+// Affects reports whether any advisory in the database affects a module version
+func (db *DB) Affects(module, version string) (bool, error) {
+	vulns, err := db.Vulnerabilities(module, version, "")
+	return len(vulns) > 0, err
+}
+
+// load reads an OSV entry, caching it for later lookups
+func (db *DB) load(id string) (*entry, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if e, ok := db.entries[id]; ok {
+		return e, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(db.dir, "ID", id+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("vulnerability database has no entry %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", id, err)
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", id, err)
+	}
+
+	db.entries[id] = &e
+	return &e, nil
+}
+
+// versionRange is an affected interval [introduced, fixed); an empty bound is open
+type versionRange struct {
+	introduced string
+	fixed      string
+}
+
+// semverRanges converts OSV SEMVER range events into affected intervals
+func semverRanges(ranges []osvRange) []versionRange {
+	var intervals []versionRange
+
+	for _, r := range ranges {
+		if r.Type != "SEMVER" {
+			continue
+		}
+
+		var open *versionRange
+		for _, event := range r.Events {
+			switch {
+			case event.Introduced != "":
+				intervals = append(intervals, versionRange{introduced: canonical(event.Introduced)})
+				open = &intervals[len(intervals)-1]
+			case event.Fixed != "" && open != nil:
+				open.fixed = canonical(event.Fixed)
+				open = nil
+			}
+		}
+	}
+
+	return intervals
+}
+
+// isAffected reports whether a version falls into any affected interval
+func isAffected(ranges []versionRange, version string) bool {
+	for _, r := range ranges {
+		if r.introduced != "" && semver.Compare(version, r.introduced) < 0 {
+			continue
+		}
+		if r.fixed != "" && semver.Compare(version, r.fixed) >= 0 {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// fixedIn returns the version that fixes the interval containing a version
+func fixedIn(ranges []versionRange, version string) string {
+	for _, r := range ranges {
+		if isAffected([]versionRange{r}, version) {
+			return r.fixed
+		}
+	}
+	return ""
+}
+
+// canonical converts an OSV version ("1.2.3", "0" for the beginning of time) to Go form
+func canonical(version string) string {
+	if version == "0" {
+		return ""
+	}
+	return "v" + version
+}